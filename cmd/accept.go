@@ -0,0 +1,65 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+var acceptStates []string
+
+// stateSoon is the state --accept knows besides the statuses: a version that
+// isn't EOL yet but reaches EOL within --soon-days.
+const stateSoon = "soon"
+
+var acceptableStates = []string{string(endoflife.StatusSupported), string(endoflife.StatusMaintenance), string(endoflife.StatusUnknown), string(endoflife.StatusEOL), stateSoon}
+
+// validateAccept makes sure --accept only lists states we know.
+func validateAccept() error {
+	for _, state := range acceptStates {
+		if !slices.Contains(acceptableStates, state) {
+			return fmt.Errorf("Invalid --accept state %q, expected any of %s", state, strings.Join(acceptableStates, ", "))
+		}
+	}
+	return nil
+}
+
+// resultStates returns the states a result is in: its status, and soon if it
+// reaches EOL within --soon-days.
+func resultStates(r Result) []string {
+	states := []string{string(r.Status)}
+	if r.Status != endoflife.StatusEOL && r.EOL != "" {
+		if days, ok := daysUntil(r.EOL); ok && days <= soonDays {
+			states = append(states, stateSoon)
+		}
+	}
+	return states
+}
+
+// acceptFailure fails the run if any result is in a state --accept doesn't
+// list. A version that is both supported and soon needs both accepted.
+// Results that couldn't be checked are left to the other fail flags.
+func acceptFailure(results []Result) error {
+	var rejected []string
+	for _, r := range results {
+		if r.Error != "" {
+			continue
+		}
+		for _, state := range resultStates(r) {
+			if !slices.Contains(acceptStates, state) {
+				rejected = append(rejected, fmt.Sprintf("%s %s (%s)", r.Product, r.Version, state))
+				break
+			}
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d version(s) are not in an accepted state: %s", len(rejected), strings.Join(rejected, ", "))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+	products := testProducts()
+	products["hugo"] = `[{"cycle":"0.120","releaseDate":"2023-10-30","latest":"0.120.4"}]`
+	api := newAPIServer(t, products)
+	// One version in each state: nodejs 22 is supported and, with
+	// --soon-days 200, soon; 20 is in maintenance; 18 is EOL; hugo has no
+	// EOL date.
+	inventory := writeFile(t, "inventory.yaml", `- product: nodejs
+  version: "22"
+- product: nodejs
+  version: "20"
+- product: nodejs
+  version: "18"
+- product: hugo
+  version: "0.120"
+`)
+
+	tests := []struct {
+		accept string
+		code   int
+		stderr string
+	}{
+		{"supported,soon,maintenance,unknown,eol", 0, ""},
+		{"supported,maintenance,unknown,eol", 1, "Error: 1 version(s) are not in an accepted state: nodejs 22 (soon)\n"},
+		{"supported,soon,maintenance,unknown", 1, "Error: 1 version(s) are not in an accepted state: nodejs 18 (eol)\n"},
+		{"supported,soon", 1, "Error: 3 version(s) are not in an accepted state: nodejs 20 (maintenance), nodejs 18 (eol), hugo 0.120 (unknown)\n"},
+		{"eol", 1, "Error: 3 version(s) are not in an accepted state: nodejs 22 (supported), nodejs 20 (maintenance), hugo 0.120 (unknown)\n"},
+		{"supported,bogus", 1, `Error: Invalid --accept state "bogus", expected any of supported, maintenance, unknown, eol, soon` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", "--soon-days", "200", "--accept", tt.accept, inventory)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestAcceptSingleCheck(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	tests := []struct {
+		version string
+		accept  string
+		code    int
+	}{
+		{"18", "eol", 0},
+		{"18", "supported,maintenance", 1},
+		{"20", "maintenance", 0},
+		{"22", "supported", 0},
+		{"22", "maintenance", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.accept, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check", "nodejs", tt.version, "--accept", tt.accept)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.code != 0 && !strings.Contains(got.stderr, "not in an accepted state") {
+				t.Errorf("stderr = %q, want the rejected state", got.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+var badgeSoonDays int
+
+// Badge is the shields.io endpoint badge schema, see
+// https://shields.io/badges/endpoint-badge.
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// statusBadge builds the badge for a cycle. Versions that aren't EOL yet but
+// will be within --soon-days get an orange badge with the EOL date.
+func statusBadge(label string, v SoftwareVersion) Badge {
+	badge := Badge{SchemaVersion: 1, Label: label}
+	status := endoflife.CycleStatus(v, today())
+	switch {
+	case status == endoflife.StatusEOL:
+		badge.Message, badge.Color = "EOL", "red"
+	case eolWithin(v, badgeSoonDays):
+		badge.Message, badge.Color = "EOL "+endoflife.EOLDate(v), "orange"
+	case status == endoflife.StatusMaintenance:
+		badge.Message, badge.Color = "maintenance", "yellow"
+	case status == endoflife.StatusUnknown:
+		badge.Message, badge.Color = "unknown", "lightgrey"
+	default:
+		badge.Message, badge.Color = "supported", "brightgreen"
+	}
+	return badge
+}
+
+// badgeCmd represents the badge command
+var badgeCmd = &cobra.Command{
+	Use:   "badge <name> <version>",
+	Short: "Print a shields.io endpoint badge for a version's EOL status",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, version := args[0], args[1]
+		label := name + " " + version
+
+		var badge Badge
+		v, provenance, err := CheckVersion(cmd.Context(), name, version)
+		switch {
+		case err == nil:
+			badge = statusBadge(label, v)
+		case provenance.Status == http.StatusOK:
+			badge = Badge{SchemaVersion: 1, Label: label, Message: "not found", Color: "lightgrey"}
+		default:
+			return err
+		}
+
+		out, err := json.Marshal(badge)
+		if err != nil {
+			return err
+		}
+		if outputFile != "" {
+			if err := os.WriteFile(outputFile, append(out, '\n'), 0o644); err != nil {
+				return fmt.Errorf("Error writing badge: %s", err)
+			}
+			return nil
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+
+	badgeCmd.Flags().IntVar(&badgeSoonDays, "soon-days", 90, "Number of days before EOL the badge turns orange")
+	badgeCmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "Write the badge to a file instead of stdout")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBadge(t *testing.T) {
+	products := testProducts()
+	products["hugo"] = `[{"cycle":"0.120","releaseDate":"2023-10-30","latest":"0.120.4"}]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"eol", []string{"nodejs", "18"}, `{"schemaVersion":1,"label":"nodejs 18","message":"EOL","color":"red"}`},
+		{"eol soon", []string{"nodejs", "22", "--soon-days", "120"}, `{"schemaVersion":1,"label":"nodejs 22","message":"EOL ` + daysFromNow(100) + `","color":"orange"}`},
+		{"supported", []string{"nodejs", "22"}, `{"schemaVersion":1,"label":"nodejs 22","message":"supported","color":"brightgreen"}`},
+		{"maintenance", []string{"python", "3.12"}, `{"schemaVersion":1,"label":"python 3.12","message":"maintenance","color":"yellow"}`},
+		{"unknown", []string{"hugo", "0.120"}, `{"schemaVersion":1,"label":"hugo 0.120","message":"unknown","color":"lightgrey"}`},
+		{"not found", []string{"nodejs", "8"}, `{"schemaVersion":1,"label":"nodejs 8","message":"not found","color":"lightgrey"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "badge"}, tt.args...)...)
+			if got.code != 0 {
+				t.Errorf("exit code = %d, want 0\nstderr: %s", got.code, got.stderr)
+			}
+			if got.stdout != tt.want+"\n" {
+				t.Errorf("stdout = %q, want %q", got.stdout, tt.want+"\n")
+			}
+		})
+	}
+
+	t.Run("output file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "badge.json")
+		got := run(t, nil, "--api-url", api.apiURL(), "badge", "nodejs", "18", "-o", path)
+		if got.code != 0 || got.stdout != "" {
+			t.Errorf("exit code %d, stdout %q, want exit code 0 and no output\nstderr: %s", got.code, got.stdout, got.stderr)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"schemaVersion":1,"label":"nodejs 18","message":"EOL","color":"red"}` + "\n"; string(data) != want {
+			t.Errorf("badge file = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "badge", "nodjs", "18")
+		if got.code != 1 || got.stdout != "" {
+			t.Errorf("exit code %d, stdout %q, want the lookup to fail", got.code, got.stdout)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spf13/cobra"
+)
+
+// EOLOrder says which of two versions reaches EOL first, and how many days
+// before the other.
+type EOLOrder struct {
+	First  Result `json:"first"`
+	Second Result `json:"second"`
+	Days   int    `json:"days"`
+}
+
+// eolOrder orders two results by their EOL dates, keeping the given order
+// when they are on the same day.
+func eolOrder(a Result, b Result) (EOLOrder, error) {
+	for _, r := range []Result{a, b} {
+		if r.EOL == "" {
+			return EOLOrder{}, fmt.Errorf("%s %s has no EOL date", capitalize(r.Product), r.Version)
+		}
+	}
+	aEOL, err := parseDay(a.EOL)
+	if err != nil {
+		return EOLOrder{}, err
+	}
+	bEOL, err := parseDay(b.EOL)
+	if err != nil {
+		return EOLOrder{}, err
+	}
+
+	if bEOL.Before(aEOL) {
+		a, b, aEOL, bEOL = b, a, bEOL, aEOL
+	}
+	return EOLOrder{First: a, Second: b, Days: int(math.Round(bEOL.Sub(aEOL).Hours() / 24))}, nil
+}
+
+// beforeCmd represents the before command
+var beforeCmd = &cobra.Command{
+	Use:   "before <name> <version> <other-name> <other-version>",
+	Short: "Show which of two versions reaches EOL first, and by how many days",
+	Long: `Show which of two versions reaches EOL first, and by how many days, e.g. to
+plan upgrading an app's runtime before its base OS is gone:
+
+  date-reaper before nodejs 20 debian 12`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		var results [2]Result
+		for i := range results {
+			name, version := args[2*i], args[2*i+1]
+			v, provenance, err := CheckVersion(ctx, name, version)
+			if err != nil {
+				return fmt.Errorf("Error checking %s %s: %s", name, version, err)
+			}
+			results[i] = newResult(name, version, v, provenance)
+		}
+
+		order, err := eolOrder(results[0], results[1])
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(order)
+		}
+
+		first, second := order.First, order.Second
+		switch order.Days {
+		case 0:
+			fmt.Printf("%s %s and %s %s both reach EOL on %s\n", capitalize(first.Product), first.Version, capitalize(second.Product), second.Version, first.EOL)
+		case 1:
+			fmt.Printf("%s %s reaches EOL 1 day before %s %s (%s vs %s)\n", capitalize(first.Product), first.Version, capitalize(second.Product), second.Version, first.EOL, second.EOL)
+		default:
+			fmt.Printf("%s %s reaches EOL %d days before %s %s (%s vs %s)\n", capitalize(first.Product), first.Version, order.Days, capitalize(second.Product), second.Version, first.EOL, second.EOL)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(beforeCmd)
+
+	addJSONFlags(beforeCmd, "the comparison")
+}
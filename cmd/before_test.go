@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBefore(t *testing.T) {
+	products := testProducts()
+	products["ubuntu"] = `[
+{"cycle":"22.04","releaseDate":"2022-04-21","eol":"2025-05-01"},
+{"cycle":"20.04","releaseDate":"2020-04-23","eol":"2025-04-30"}
+]`
+	products["deno"] = `[{"cycle":"2","releaseDate":"2024-10-09","eol":false,"latest":"2.1.4"}]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"first given first", []string{"nodejs", "18", "python", "3.9"}, 0, "Nodejs 18 reaches EOL 184 days before Python 3.9 (2025-04-30 vs 2025-10-31)\n", ""},
+		{"second given first", []string{"python", "3.9", "nodejs", "18"}, 0, "Nodejs 18 reaches EOL 184 days before Python 3.9 (2025-04-30 vs 2025-10-31)\n", ""},
+		{"one day", []string{"ubuntu", "22.04", "nodejs", "18.20.4"}, 0, "Nodejs 18.20.4 reaches EOL 1 day before Ubuntu 22.04 (2025-04-30 vs 2025-05-01)\n", ""},
+		{"same day", []string{"ubuntu", "20.04", "nodejs", "18"}, 0, "Ubuntu 20.04 and Nodejs 18 both reach EOL on 2025-04-30\n", ""},
+		{"no eol date", []string{"nodejs", "18", "deno", "2"}, 1, "", "Error: Deno 2 has no EOL date\n"},
+		{"unknown version", []string{"nodejs", "8", "python", "3.9"}, 1, "", "Error: Error checking nodejs 8: Version not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "before"}, tt.args...)...)
+			if got.code != tt.code || got.stdout != tt.stdout {
+				t.Errorf("exit code %d, stdout %q, want exit code %d and stdout %q\nstderr: %s", got.code, got.stdout, tt.code, tt.stdout, got.stderr)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "before", "python", "3.9", "nodejs", "18", "--json")
+		var order EOLOrder
+		if err := json.Unmarshal([]byte(got.stdout), &order); err != nil {
+			t.Fatalf("parsing %q: %s", got.stdout, err)
+		}
+		if order.First.Product != "nodejs" || order.Second.Product != "python" || order.Days != 184 {
+			t.Errorf("order = %s %s, %s %s, %d days, want nodejs 18 first by 184 days", order.First.Product, order.First.Version, order.Second.Product, order.Second.Version, order.Days)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// renovateConfig is the part of a renovate.json that names runtime versions:
+// the constraints Renovate resolves updates against, and package rules that
+// hold a dependency on allowed versions.
+type renovateConfig struct {
+	Constraints  map[string]string `yaml:"constraints"`
+	PackageRules []struct {
+		MatchPackageNames []string `yaml:"matchPackageNames"`
+		MatchDepNames     []string `yaml:"matchDepNames"`
+		AllowedVersions   string   `yaml:"allowedVersions"`
+	} `yaml:"packageRules"`
+}
+
+// dependabotConfig is the part of a .github/dependabot.yml that restricts
+// which versions of a dependency are proposed.
+type dependabotConfig struct {
+	Updates []struct {
+		Ecosystem string `yaml:"package-ecosystem"`
+		Ignore    []struct {
+			DependencyName string   `yaml:"dependency-name"`
+			Versions       []string `yaml:"versions"`
+			UpdateTypes    []string `yaml:"update-types"`
+		} `yaml:"ignore"`
+	} `yaml:"updates"`
+}
+
+var botTargetPattern = regexp.MustCompile(`^[\^~=v]*(\d+(?:\.\d+)*)(?:\.[x*])?$`)
+
+// botProduct maps the name an update bot knows a runtime by, which is usually
+// its Docker image or tool name, to an endoflife.date product.
+func botProduct(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if name == "go" {
+		return "go", true
+	}
+	product, ok := imageProducts[name]
+	return product, ok
+}
+
+// botTarget picks the version a bot constraint targets. Exact versions,
+// caret and tilde ranges and x-ranges like 18.x name one; anything else
+// only bounds a range.
+func botTarget(constraint string) (string, bool) {
+	match := botTargetPattern.FindStringSubmatch(strings.TrimSpace(constraint))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+func botRef(source string, name string, constraint string) checkRef {
+	product, ok := botProduct(name)
+	if !ok {
+		return checkRef{Source: source, Skip: fmt.Sprintf("%s, it isn't a runtime tracked by endoflife.date", name)}
+	}
+	version, ok := botTarget(constraint)
+	if !ok {
+		return checkRef{Source: source, Skip: fmt.Sprintf("%s %s, it only bounds a range, not a version", name, constraint)}
+	}
+	return checkRef{Source: source, Label: fmt.Sprintf("%s %s", name, constraint), Product: product, Version: version}
+}
+
+// renovateRefs extracts the runtime versions a Renovate config targets.
+func renovateRefs(config renovateConfig) []checkRef {
+	var refs []checkRef
+	for _, name := range sortedKeys(config.Constraints) {
+		refs = append(refs, botRef("constraints", name, config.Constraints[name]))
+	}
+	for i, rule := range config.PackageRules {
+		if rule.AllowedVersions == "" {
+			continue
+		}
+		source := fmt.Sprintf("packageRules[%d]", i)
+		for _, name := range append(rule.MatchPackageNames, rule.MatchDepNames...) {
+			refs = append(refs, botRef(source, name, rule.AllowedVersions))
+		}
+	}
+	return refs
+}
+
+// dependabotCapPattern matches a Dependabot ignore range that drops every
+// version from some point on, like ">= 21" or "> 2.0".
+var dependabotCapPattern = regexp.MustCompile(`^>\s*(=?)\s*v?(\d+(?:\.\d+)*)(?:\.[x*])?$`)
+
+// dependabotRefs extracts the runtime versions a Dependabot config holds a
+// dependency on. Dependabot only ignores versions, so an ignore rule names
+// one when it drops everything from some version on: the target is then the
+// newest cycle below it. Other rules, like ignoring one version or an update
+// type, don't cap the version and are reported as skipped.
+func dependabotRefs(config dependabotConfig) []checkRef {
+	var refs []checkRef
+	for i, update := range config.Updates {
+		for _, ignore := range update.Ignore {
+			product, ok := botProduct(ignore.DependencyName)
+			if !ok {
+				continue
+			}
+			source := fmt.Sprintf("updates[%d] (%s)", i, update.Ecosystem)
+
+			// With several caps, the lowest one holds.
+			ref := checkRef{Source: source, Product: product}
+			for _, rule := range ignore.Versions {
+				match := dependabotCapPattern.FindStringSubmatch(strings.TrimSpace(rule))
+				if match == nil {
+					continue
+				}
+				orEqual := match[1] == ""
+				if order := endoflife.CompareVersions(match[2], ref.Below); ref.Below == "" || order < 0 || (order == 0 && !orEqual) {
+					ref.Label = fmt.Sprintf("%s ignoring %s", ignore.DependencyName, strings.TrimSpace(rule))
+					ref.Below, ref.OrEqual = match[2], orEqual
+				}
+			}
+			if ref.Below == "" {
+				rules := append(ignore.Versions, ignore.UpdateTypes...)
+				ref = checkRef{Source: source, Skip: fmt.Sprintf("%s ignoring %s, which doesn't cap the version", ignore.DependencyName, strings.Join(rules, ", "))}
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// newestBelow finds the newest cycle of a product below a version, or at it
+// too with orEqual: the one an update bot ignoring everything above stays on.
+func newestBelow(ctx context.Context, product string, ceiling string, orEqual bool) (string, error) {
+	versions, _, err := fetchCycles(ctx, product)
+	if err != nil {
+		return "", err
+	}
+	var newest string
+	for _, v := range versions {
+		order := endoflife.CompareVersions(v.Cycle, ceiling)
+		if (order < 0 || (orEqual && order == 0)) && (newest == "" || endoflife.CompareVersions(v.Cycle, newest) > 0) {
+			newest = v.Cycle
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no %s release is below %s", product, ceiling)
+	}
+	return newest, nil
+}
+
+// checkBotConfigCmd represents the check-bot-config command
+var checkBotConfigCmd = &cobra.Command{
+	Use:         "check-bot-config <path-to-renovate.json-or-dependabot.yml>",
+	Annotations: map[string]string{inputsAnnotation: "renovate.json,dependabot.yml"},
+	Short:       "Check the runtime versions an update bot targets for EOL",
+	Long: `Check the runtime versions a Renovate config (constraints and the
+allowedVersions of packageRules) or a Dependabot config (ignore rules that
+drop every version from some point on, like ">= 21") targets, so the
+versions your update bot moves you to aren't EOL themselves.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("Error reading config file: %s", err)
+		}
+
+		// JSON is YAML too, so one parser handles both bots' configs.
+		var shape map[string]interface{}
+		if err := yaml.Unmarshal(data, &shape); err != nil {
+			return fmt.Errorf("Error parsing config file: %s", err)
+		}
+
+		var refs []checkRef
+		if _, ok := shape["updates"]; ok {
+			var config dependabotConfig
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("Error parsing Dependabot config: %s", err)
+			}
+			refs = dependabotRefs(config)
+		} else {
+			var config renovateConfig
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("Error parsing Renovate config: %s", err)
+			}
+			refs = renovateRefs(config)
+		}
+		return checkRefs(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkBotConfigCmd)
+
+	addMinRemainingFlag(checkBotConfigCmd)
+	addRemediationFlag(checkBotConfigCmd)
+	addJSONFlags(checkBotConfigCmd, "the report of the targeted versions")
+	addPlanFlag(checkBotConfigCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckBotConfig(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	tests := []struct {
+		name   string
+		file   string
+		config string
+		code   int
+		stdout []string
+	}{
+		{"dependabot caps", "dependabot.yml", `version: 2
+updates:
+  - package-ecosystem: docker
+    directory: /
+    ignore:
+      - dependency-name: node
+        versions: ["19.x", ">= 21", ">= 23"]
+      - dependency-name: python
+        versions: ["> 3.9"]
+      - dependency-name: golang
+        update-types: ["version-update:semver-major"]
+      - dependency-name: some-lib
+        versions: [">= 2"]
+`, 1, []string{
+			"updates[0] (docker): Nodejs 20 is in maintenance mode until " + daysFromNow(2000),
+			"updates[0] (docker): Python 3.9 is EOL since 2025-10-31",
+			"updates[0] (docker): skipped golang ignoring version-update:semver-major, which doesn't cap the version",
+		}},
+		{"dependabot cap below every release", "dependabot.yml", `updates:
+  - package-ecosystem: docker
+    ignore:
+      - dependency-name: node
+        versions: [">= 18"]
+`, 0, []string{
+			"updates[0] (docker): error checking node ignoring >= 18: no nodejs release is below 18",
+		}},
+		{"renovate", "renovate.json", `{
+  "constraints": {"node": "^22.0.0", "python": ">=3.9"},
+  "packageRules": [{"matchPackageNames": ["python"], "allowedVersions": "3.12.x"}]
+}`, 0, []string{
+			"constraints: Nodejs 22.0.0 is not EOL yet. It will be EOL on " + daysFromNow(100),
+			"constraints: skipped python >=3.9, it only bounds a range, not a version",
+			"packageRules[0]: Python 3.12 is in maintenance mode until " + daysFromNow(1500),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := writeFile(t, tt.file, tt.config)
+			got := run(t, nil, "--api-url", api.apiURL(), "check-bot-config", config)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if want := strings.Join(tt.stdout, "\n") + "\n"; got.stdout != want {
+				t.Errorf("stdout = %q, want %q", got.stdout, want)
+			}
+		})
+	}
+}
+
+func TestCheckBotConfigPlan(t *testing.T) {
+	config := writeFile(t, "dependabot.yml", `updates:
+  - package-ecosystem: docker
+    ignore:
+      - dependency-name: node
+        versions: [">= 21"]
+      - dependency-name: python
+        versions: ["> 3.12"]
+`)
+	got := run(t, nil, "--no-cache", "check-bot-config", "--plan", config)
+	for _, want := range []string{"  nodejs <21 (network)\n", "  python <=3.12 (network)\n"} {
+		if !strings.Contains(got.stdout, want) {
+			t.Errorf("stdout = %q, want it to contain %q", got.stdout, want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var concurrency int
+
+// evaluateItems evaluates inventory items concurrently. If the run is
+// cancelled (Ctrl-C or --deadline) it stops handing out work and returns the
+// results of the items that did finish, in inventory order, along with an
+// incompleteError.
+func evaluateItems(ctx context.Context, items []InventoryItem) ([]Result, error) {
+	results := make([]Result, len(items))
+	finished := make([]bool, len(items))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, concurrency); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := evaluate(ctx, items[i].Product, items[i].Version)
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+				result.Source = items[i].Source
+				// Every index is handed out once, so no locking is needed.
+				results[i], finished[i] = result, true
+			}
+		}()
+	}
+
+feed:
+	for i := range items {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var completed []Result
+	for i, result := range results {
+		if finished[i] {
+			completed = append(completed, result)
+		}
+	}
+	if len(completed) < len(items) && ctx.Err() != nil {
+		return completed, incompleteError(ctx, len(completed), len(items))
+	}
+	return completed, nil
+}
+
+// bulkVerdict decides how a bulk run ends: incomplete if it was cut short,
+// otherwise failed if any version isn't in an --accept state or, without
+// --accept, if any version is EOL or too close to it for --min-remaining. A
+// run that passed can still end with --warn-exit-code, and any run with
+// exitNetworkUsed, see networkVerdict.
+func bulkVerdict(results []Result, incomplete error) error {
+	return networkVerdict(resultsVerdict(results, incomplete))
+}
+
+func resultsVerdict(results []Result, incomplete error) error {
+	if incomplete != nil {
+		return incomplete
+	}
+	attributeFailures(results)
+	if len(acceptStates) > 0 {
+		if err := acceptFailure(results); err != nil {
+			return err
+		}
+		return soonWarning(results)
+	}
+	eolCount := 0
+	for _, result := range results {
+		if result.IsEOL {
+			eolCount++
+		}
+	}
+	if eolCount > 0 {
+		return fmt.Errorf("%d version(s) are %w", eolCount, errEOL)
+	}
+	if err := minRemainingFailure(results); err != nil {
+		return err
+	}
+	return soonWarning(results)
+}
+
+// attributeFailures sets the FailedBy of the results that fail a bulk run
+// under the same rules as bulkVerdict, logging each with -v.
+func attributeFailures(results []Result) {
+	for i := range results {
+		r := &results[i]
+		if r.Error != "" {
+			continue
+		}
+		switch {
+		case len(acceptStates) > 0:
+			for _, state := range resultStates(*r) {
+				if !slices.Contains(acceptStates, state) {
+					r.FailedBy = "--accept"
+					break
+				}
+			}
+		case r.IsEOL:
+			r.FailedBy = failedByEOL
+		case remainingTooShort(r.EOL):
+			r.FailedBy = "--min-remaining"
+		}
+		if r.FailedBy != "" {
+			logf("%s %s failed by %s", r.Product, r.Version, r.FailedBy)
+		}
+	}
+}
+
+// checkRef is a product version found in a file, e.g. a base image or a
+// pinned package. Source says where in the file it was found and Label how
+// it was written there. Skip explains why an entry isn't checked. Below,
+// set instead of Version, checks the newest cycle below that version, or
+// at it too with OrEqual.
+type checkRef struct {
+	Source  string
+	Label   string
+	Product string
+	Version string
+	Below   string
+	OrEqual bool
+	Skip    string
+}
+
+// versionText is how a ref's version reads before any Below is resolved,
+// for --plan and errors.
+func (ref checkRef) versionText() string {
+	switch {
+	case ref.Below == "":
+		return ref.Version
+	case ref.OrEqual:
+		return "<=" + ref.Below
+	default:
+		return "<" + ref.Below
+	}
+}
+
+// checkRefs checks a list of product versions found in a file, printing each
+// result as it goes (or all of them as JSON at the end), and returns the
+// verdict of the run.
+func checkRefs(ctx context.Context, refs []checkRef) error {
+	if plan {
+		var items []InventoryItem
+		for _, ref := range refs {
+			if ref.Skip == "" {
+				items = append(items, InventoryItem{Product: ref.Product, Version: ref.versionText()})
+			}
+		}
+		showPlan(items)
+		return nil
+	}
+
+	var results []Result
+	var incomplete error
+	for i, ref := range refs {
+		if ref.Skip != "" {
+			if !jsonOutput {
+				fmt.Printf("%s: skipped %s\n", ref.Source, ref.Skip)
+			}
+			continue
+		}
+
+		var result Result
+		version, err := ref.Version, error(nil)
+		if ref.Below != "" {
+			version, err = newestBelow(ctx, ref.Product, ref.Below, ref.OrEqual)
+			if err != nil {
+				result = errorResult(ref.Product, ref.versionText(), Provenance{}, err)
+			}
+		}
+		if err == nil {
+			result, err = evaluate(ctx, ref.Product, version)
+		}
+		if err != nil && ctx.Err() != nil {
+			incomplete = incompleteError(ctx, i, len(refs))
+			break
+		}
+		result.Source = ref.Source
+		results = append(results, result)
+
+		if !jsonOutput {
+			if err != nil {
+				fmt.Printf("%s: error checking %s: %s\n", ref.Source, ref.Label, err)
+			} else {
+				fmt.Printf("%s: %s\n", ref.Source, describeResult(result))
+			}
+		}
+	}
+
+	verdict := bulkVerdict(results, incomplete)
+	if jsonOutput {
+		if err := printJSON(newReport(results, verdict)); err != nil {
+			return err
+		}
+	}
+	remediate(ctx, results, verdict)
+	return verdict
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptedRunReportsPartialResults(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: python\n  version: \"3.12\"\n")
+
+	for _, signal := range []os.Signal{os.Interrupt, syscall.SIGTERM} {
+		t.Run(signal.String(), func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			api.slow("python", 0, 10*time.Second)
+			cmd := command(t, nil, "--api-url", api.apiURL(), "check-inventory", "--json", "-c", "1", inventory)
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			// nodejs is done once python was asked for.
+			for start := time.Now(); api.count("python") == 0; time.Sleep(10 * time.Millisecond) {
+				if time.Since(start) > 5*time.Second {
+					cmd.Process.Kill()
+					t.Fatalf("python was never fetched\nstderr: %s", stderr.String())
+				}
+			}
+			cmd.Process.Signal(signal)
+
+			var exitErr *exec.ExitError
+			if err := cmd.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != exitIncomplete {
+				t.Errorf("run ended with %v, want exit code %d\nstderr: %s", err, exitIncomplete, stderr.String())
+			}
+			if want := "run was cancelled after checking 1 of 2 items"; !strings.Contains(stderr.String(), want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), want)
+			}
+			var report Report
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("parsing report: %s\n%s", err, stdout.String())
+			}
+			if !report.Incomplete || report.ExitCode != exitIncomplete {
+				t.Errorf("report has incomplete %t, exitCode %d", report.Incomplete, report.ExitCode)
+			}
+			if len(report.Results) != 1 || report.Results[0].Product != "nodejs" {
+				t.Errorf("results = %+v, want only nodejs", report.Results)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Product responses are cached on disk so repeated runs don't have to hit
+// endoflife.date every time. Three flags control how the cache is used:
+//
+//	--no-cache      never read from the cache, but still store fresh responses
+//	--refresh       re-fetch even if the cached copy is fresh, falling back to
+//	                the cached copy when the network request fails
+//	--network-only  ignore the cache completely (no reads, no writes, no
+//	                fallback), guaranteeing the run only sees fresh data
+//
+// Without any of them, a cached copy younger than cacheTTL is used as is and
+// older copies are only used as a fallback when the network request fails.
+// The three flags are mutually exclusive (see conflictingFlags).
+var noCache bool
+var refreshCache bool
+var networkOnly bool
+
+const cacheTTL = 24 * time.Hour
+
+// cacheReadable reports whether cached responses may be used for this run.
+func cacheReadable() bool {
+	return !noCache && !networkOnly
+}
+
+// cacheWritable reports whether fresh responses should be stored for this run.
+func cacheWritable() bool {
+	return !networkOnly
+}
+
+// cacheDirFlag is --cache-dir, which like $DATE_REAPER_CACHE_DIR moves the
+// cache out of the user's cache directory, e.g. to one CI persists.
+var cacheDirFlag string
+
+// cacheDir returns the directory the cache lives in: --cache-dir, then
+// $DATE_REAPER_CACHE_DIR, then date-reaper in the user's cache directory
+// ($XDG_CACHE_HOME or ~/.cache on Linux).
+func cacheDir() (string, error) {
+	if cacheDirFlag != "" {
+		return cacheDirFlag, nil
+	}
+	if dir := os.Getenv("DATE_REAPER_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "date-reaper"), nil
+}
+
+func cachePath(name string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(name)+".json"), nil
+}
+
+// readCache returns the cached response for name along with the time it was
+// stored.
+func readCache(name string) ([]byte, time.Time, error) {
+	path, err := cachePath(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return body, info.ModTime(), nil
+}
+
+func writeCache(name string, body []byte) error {
+	path, err := cachePath(name)
+	if err != nil {
+		return err
+	}
+	// Only the user should see what they looked up, as the XDG base
+	// directory spec asks of new directories.
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so concurrent checks never read a
+	// half-written response.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheFlags(t *testing.T) {
+	const seeded = `[{"cycle":"22","releaseDate":"2024-04-24","eol":false,"latest":"22.0.0"}]`
+
+	tests := []struct {
+		name     string
+		seed     bool
+		args     []string
+		requests int
+		cache    string // what the cache holds afterwards: "seeded", "fresh" or "" for nothing
+	}{
+		{"hit", true, nil, 0, "seeded"},
+		{"miss", false, nil, 1, "fresh"},
+		{"no-cache skips reads but writes", true, []string{"--no-cache"}, 1, "fresh"},
+		{"refresh re-fetches", true, []string{"--refresh"}, 1, "fresh"},
+		{"network-only leaves the cache alone", true, []string{"--network-only"}, 1, "seeded"},
+		{"network-only doesn't write", false, []string{"--network-only"}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			dir := t.TempDir()
+			path := filepath.Join(dir, "nodejs.json")
+			if tt.seed {
+				if err := os.WriteFile(path, []byte(seeded), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			args := append([]string{"--api-url", api.apiURL(), "check", "nodejs", "22"}, tt.args...)
+			got := run(t, []string{"DATE_REAPER_CACHE_DIR=" + dir}, args...)
+			if got.code != 0 {
+				t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+			}
+			if n := api.count("nodejs"); n != tt.requests {
+				t.Errorf("%d request(s), want %d", n, tt.requests)
+			}
+
+			body, err := os.ReadFile(path)
+			switch {
+			case tt.cache == "" && err == nil:
+				t.Errorf("cache was written: %s", body)
+			case tt.cache == "seeded" && string(body) != seeded:
+				t.Errorf("cache = %q, want it untouched", body)
+			case tt.cache == "fresh" && string(body) != testProducts()["nodejs"]:
+				t.Errorf("cache = %q, want the fresh response", body)
+			}
+		})
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	tests := []struct {
+		name string
+		// where returns the environment and arguments for a run, and the
+		// cache directory it should use.
+		where func(dir string) (env []string, args []string, want string)
+	}{
+		{"--cache-dir", func(dir string) ([]string, []string, string) {
+			return nil, []string{"--cache-dir", filepath.Join(dir, "flag")}, filepath.Join(dir, "flag")
+		}},
+		{"DATE_REAPER_CACHE_DIR", func(dir string) ([]string, []string, string) {
+			return []string{"DATE_REAPER_CACHE_DIR=" + filepath.Join(dir, "env")}, nil, filepath.Join(dir, "env")
+		}},
+		{"--cache-dir wins over DATE_REAPER_CACHE_DIR", func(dir string) ([]string, []string, string) {
+			return []string{"DATE_REAPER_CACHE_DIR=" + filepath.Join(dir, "env")}, []string{"--cache-dir", filepath.Join(dir, "flag")}, filepath.Join(dir, "flag")
+		}},
+		{"XDG_CACHE_HOME", func(dir string) ([]string, []string, string) {
+			return []string{"DATE_REAPER_CACHE_DIR=", "XDG_CACHE_HOME=" + filepath.Join(dir, "xdg")}, nil, filepath.Join(dir, "xdg", "date-reaper")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			env, args, want := tt.where(t.TempDir())
+			got := run(t, env, append([]string{"--api-url", api.apiURL(), "check", "nodejs", "22"}, args...)...)
+			if got.code != 0 {
+				t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+			}
+
+			body, err := os.ReadFile(filepath.Join(want, "nodejs.json"))
+			if err != nil {
+				t.Fatalf("the response wasn't cached in %s: %s", want, err)
+			}
+			if string(body) != testProducts()["nodejs"] {
+				t.Errorf("cache = %q, want the response", body)
+			}
+			info, err := os.Stat(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o700 {
+				t.Errorf("cache directory mode = %o, want 700", perm)
+			}
+
+			// A second run reads what the first one cached.
+			run(t, env, append([]string{"--api-url", api.apiURL(), "check", "nodejs", "22"}, args...)...)
+			if n := api.count("nodejs"); n != 1 {
+				t.Errorf("%d requests, want the second run served from the cache", n)
+			}
+		})
+	}
+}
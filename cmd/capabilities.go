@@ -0,0 +1,68 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// inputsAnnotation marks a command that checks versions read from an input,
+// listing the input formats it understands, comma-separated.
+const inputsAnnotation = "date-reaper/inputs"
+
+// Input is a command that checks the versions found in some kind of input.
+type Input struct {
+	Command string   `json:"command"`
+	Formats []string `json:"formats"`
+}
+
+// Capabilities is what this build of date-reaper can read and write.
+type Capabilities struct {
+	Inputs  []Input  `json:"inputs"`
+	Outputs []string `json:"outputs"`
+}
+
+// capabilities collects the input commands from their annotations and the
+// output formats from the formatters.
+func capabilities() Capabilities {
+	caps := Capabilities{Outputs: formatNames()}
+	for _, cmd := range rootCmd.Commands() {
+		formats, ok := cmd.Annotations[inputsAnnotation]
+		if !ok {
+			continue
+		}
+		caps.Inputs = append(caps.Inputs, Input{Command: cmd.Name(), Formats: strings.Split(formats, ",")})
+	}
+	return caps
+}
+
+// capabilitiesCmd represents the capabilities command
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "List the input and output formats this build supports",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caps := capabilities()
+		if jsonOutput {
+			return printJSON(caps)
+		}
+
+		fmt.Println("Inputs:")
+		for _, input := range caps.Inputs {
+			fmt.Printf("  %s: %s\n", input.Command, strings.Join(input.Formats, ", "))
+		}
+		fmt.Printf("Output formats: %s\n", strings.Join(caps.Outputs, ", "))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(capabilitiesCmd)
+
+	capabilitiesCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the capabilities as JSON")
+}
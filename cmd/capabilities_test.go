@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	got := run(t, nil, "capabilities", "--json")
+	if got.code != 0 {
+		t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+	}
+	var caps Capabilities
+	if err := json.Unmarshal([]byte(got.stdout), &caps); err != nil {
+		t.Fatalf("parsing %q: %s", got.stdout, err)
+	}
+
+	wantOutputs := []string{"csv", "env", "grouped", "json", "openmetrics", "table", "text"}
+	if !reflect.DeepEqual(caps.Outputs, wantOutputs) {
+		t.Errorf("outputs = %v, want %v", caps.Outputs, wantOutputs)
+	}
+
+	inputs := map[string][]string{}
+	for _, input := range caps.Inputs {
+		inputs[input.Command] = input.Formats
+	}
+	for command, formats := range map[string][]string{
+		"check-chunk":        {"chunk.yaml"},
+		"check-compose":      {"docker-compose.yml"},
+		"check-dockerfile":   {"Dockerfile"},
+		"check-go-deps":      {"go.mod"},
+		"check-requirements": {"requirements.txt"},
+		"check-ci":           {".gitlab-ci.yml", "github-workflow"},
+	} {
+		if !reflect.DeepEqual(inputs[command], formats) {
+			t.Errorf("%s formats = %v, want %v", command, inputs[command], formats)
+		}
+	}
+	for _, command := range []string{"check", "list", "capabilities"} {
+		if _, ok := inputs[command]; ok {
+			t.Errorf("%s is listed as an input command", command)
+		}
+	}
+
+	got = run(t, nil, "capabilities")
+	if !strings.HasPrefix(got.stdout, "Inputs:\n  check-bot-config: renovate.json, dependabot.yml\n") ||
+		!strings.HasSuffix(got.stdout, "\nOutput formats: csv, env, grouped, json, openmetrics, table, text\n") {
+		t.Errorf("stdout = %q", got.stdout)
+	}
+}
@@ -5,27 +5,20 @@ Copyright © 2023 Filip Troníček
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
-	"time"
 
+	"github.com/filiptronicek/date-reaper/endoflife"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v2"
 )
 
-type SoftwareVersion struct {
-	Cycle             string      `json:"cycle"`
-	ReleaseDate       string      `json:"releaseDate"`
-	Support           interface{} `json:"support"`
-	EOL               string      `json:"eol"`
-	Latest            string      `json:"latest"`
-	LatestReleaseDate string      `json:"latestReleaseDate"`
-	LTS               bool        `json:"lts"`
-}
+type SoftwareVersion = endoflife.SoftwareVersion
 
 type Variant struct {
 	Name string            `yaml:"name"`
@@ -42,77 +35,271 @@ func capitalize(word string) string {
 	return strings.ToUpper(string(word[0])) + word[1:]
 }
 
-func CheckVersion(name string, version string) (SoftwareVersion, error) {
-	httpClient := &http.Client{}
-	req, err := http.NewRequest("GET", "https://endoflife.date/api/"+name+".json", nil)
+// fetchCycles returns all release cycles of a product.
+func fetchCycles(ctx context.Context, name string) ([]SoftwareVersion, Provenance, error) {
+	return cliClient.Cycles(ctx, name)
+}
+
+func CheckVersion(ctx context.Context, name string, version string) (SoftwareVersion, Provenance, error) {
+	versions, provenance, err := fetchCycles(ctx, name)
 	if err != nil {
-		return SoftwareVersion{}, err
+		return SoftwareVersion{}, provenance, err
 	}
 
-	req.Header.Set("User-Agent", "date-reaper-cli")
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return SoftwareVersion{}, err
+	if v, ok := matchCycle(versions, version); ok {
+		return v, provenance, nil
+	}
+	return SoftwareVersion{}, provenance, endoflife.ErrVersionNotFound
+}
+
+var tool string
+
+// chunkPaths expands the check-chunk arguments, which may be glob patterns,
+// into the chunk files to check. A pattern matching nothing is kept as is, so
+// reading it reports the missing file.
+func chunkPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern %q: %s", arg, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{arg}
+		}
+		paths = append(paths, matches...)
 	}
-	defer resp.Body.Close()
+	return paths, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return SoftwareVersion{}, fmt.Errorf("Error: Server returned status %d", resp.StatusCode)
+// checkChunk checks the variants of one chunk file, printing each result as
+// it goes unless the output is JSON. It stops early, returning an
+// incompleteError, if the run is cancelled.
+func checkChunk(ctx context.Context, chunkPath string) ([]Result, error) {
+	chunkFile, err := os.ReadFile(chunkPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading chunk file: %s", err)
 	}
 
-	var versions []SoftwareVersion
-	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
-		return SoftwareVersion{}, err
+	var chunk Chunk
+	if err := yaml.Unmarshal(chunkFile, &chunk); err != nil {
+		return nil, fmt.Errorf("Error parsing YAML: %s", err)
 	}
 
-	for _, v := range versions {
-		if v.Cycle == version {
-			return v, nil
+	var results []Result
+	for i, variant := range chunk.Variants {
+		version := variant.Name
+		v, provenance, err := CheckVersion(ctx, tool, variant.Name)
+		if err != nil && ctx.Err() != nil {
+			return results, incompleteError(ctx, i, len(chunk.Variants))
+		}
+		if err != nil {
+			results = append(results, errorResult(tool, version, provenance, err))
+		} else {
+			results = append(results, newResult(tool, version, v, provenance))
+		}
+		if jsonOutput || chunkGroupBy != "" {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error checking version %s: %s\n", version, err)
+			continue
+		}
+
+		switch endoflife.CycleStatus(v, today()) {
+		case endoflife.StatusEOL:
+			fmt.Printf("Version %s is EOL since %s. Support ended on: %s\n", version, eolText(v), endoflife.SupportEndDate(v))
+		case endoflife.StatusMaintenance:
+			fmt.Printf("Version %s is in maintenance mode until %s.\n", version, eolText(v))
+		default:
+			fmt.Printf("Version %s is not EOL yet. It will be EOL on %s.\n", version, eolText(v))
 		}
 	}
-	return SoftwareVersion{}, errors.New("Version not found")
+	return results, nil
 }
 
-var tool string
-
 var checkChunkCmd = &cobra.Command{
-	Use:  "check-chunk <path-to-chunk.yaml>",
-	Long: "Checks a chunk.yaml's variants for those which are EOL'd",
-	Args: cobra.ExactArgs(1),
+	Use:         "check-chunk <path-to-chunk.yaml>...",
+	Long:        "Checks a chunk.yaml's variants for those which are EOL'd. Paths may be glob patterns like chunks/*/chunk.yaml.",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{inputsAnnotation: "chunk.yaml"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		chunkPath := args[0]
-		chunkFile, err := os.ReadFile(chunkPath)
+		paths, err := chunkPaths(args)
 		if err != nil {
-			return fmt.Errorf("Error reading chunk file: %s", err)
+			return err
+		}
+		if chunkGroupBy != "" && chunkGroupBy != "eol-quarter" {
+			return fmt.Errorf("Invalid --group-by %q, expected eol-quarter", chunkGroupBy)
 		}
 
-		var chunk Chunk
-		if err := yaml.Unmarshal(chunkFile, &chunk); err != nil {
-			return fmt.Errorf("Error parsing YAML: %s", err)
+		ctx := cmd.Context()
+		if tool == "" {
+			return errors.New("--tool is required")
+		}
+		if err := validateProduct(ctx, tool); err != nil {
+			return err
 		}
 
-		for _, variant := range chunk.Variants {
-			version := variant.Name
-			v, err := CheckVersion(tool, variant.Name)
+		var results []Result
+		var incomplete error
+		digest := Digest{Tool: tool}
+		for _, path := range paths {
+			if len(paths) > 1 && !jsonOutput && chunkGroupBy == "" {
+				fmt.Printf("%s:\n", path)
+			}
+			chunkResults, err := checkChunk(ctx, path)
+			if len(paths) > 1 {
+				for i := range chunkResults {
+					chunkResults[i].Source = path
+				}
+			}
+			results = append(results, chunkResults...)
+			digest.add(path, chunkResults)
 			if err != nil {
-				fmt.Printf("Error checking version %s: %s\n", version, err)
-				continue
+				if exitCode(err) != exitIncomplete {
+					return err
+				}
+				incomplete = err
+				break
 			}
+		}
 
-			now := time.Now().Format("2006-01-02")
-			if v.EOL <= now {
-				fmt.Printf("Version %s is EOL since %s. Support ended on: %s\n", version, v.EOL, v.Support)
-			} else {
-				fmt.Printf("Version %s is not EOL yet. It will be EOL on %s.\n", version, v.EOL)
+		verdict := networkVerdict(incomplete)
+		if jsonOutput {
+			if err := printJSON(newReport(results, verdict)); err != nil {
+				return err
 			}
+		} else if chunkGroupBy != "" {
+			printEOLQuarters(results)
 		}
-
-		return nil
+		if webhookURL != "" {
+			digest.Incomplete = incomplete != nil
+			if err := postDigest(ctx, digest); err != nil {
+				if incomplete == nil {
+					return err
+				}
+				// The run being incomplete is what its exit code reports.
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+		return verdict
 	},
 }
 
+// printLatestNote points out patch drift within a cycle, e.g. 18.0.0 when
+// 18.20.4 is out.
+func printLatestNote(v SoftwareVersion, version string) {
+	if !behindLatest(v, version) {
+		return
+	}
+	if v.LatestReleaseDate != "" {
+		fmt.Printf("Note: %s is behind the latest %s release, %s (released %s)\n", version, v.Cycle, v.Latest, v.LatestReleaseDate)
+	} else {
+		fmt.Printf("Note: %s is behind the latest %s release, %s\n", version, v.Cycle, v.Latest)
+	}
+}
+
 var failOnMissing bool
 var failOnUnsupported bool
+var failOnMaintenance bool
+var failOnSoon bool
+var failOnUnknown bool
+var soonDays int
+var strict bool
+var failIfEOLBefore string
+
+// applyStrict turns on every fail condition --strict stands for:
+// --fail-on-unsupported, --fail-on-soon (with the current --soon-days) and
+// --fail-on-unknown.
+func applyStrict() {
+	if strict {
+		failOnUnsupported = true
+		failOnSoon = true
+		failOnUnknown = true
+	}
+}
+
+// strictFlag names a fail-on flag for failedBy, noting when --strict is what
+// turned it on.
+func strictFlag(flag string) string {
+	if strict {
+		return flag + " (via --strict)"
+	}
+	return flag
+}
+
+// policyFailure returns why a version that isn't EOL yet should still fail
+// the check under the fail-on flags, and the flag responsible, or nil if it
+// shouldn't.
+func policyFailure(name string, version string, v SoftwareVersion) (string, error) {
+	now := today()
+	if failOnUnsupported && supportEnded(v, now) {
+		return strictFlag("--fail-on-unsupported"), fmt.Errorf("%s %s is not supported anymore", capitalize(name), version)
+	}
+	if failOnMaintenance && endoflife.CycleStatus(v, now) == endoflife.StatusMaintenance {
+		return "--fail-on-maintenance", fmt.Errorf("%s %s is in maintenance mode", capitalize(name), version)
+	}
+	if failOnUnknown && endoflife.CycleStatus(v, now) == endoflife.StatusUnknown {
+		return strictFlag("--fail-on-unknown"), fmt.Errorf("%s %s has no known EOL date", capitalize(name), version)
+	}
+	if failIfEOLBefore != "" {
+		if eol := endoflife.EOLDate(v); eol != "" && eol < failIfEOLBefore {
+			return "--fail-if-eol-before", fmt.Errorf("%s %s reaches EOL on %s, before %s", capitalize(name), version, eol, failIfEOLBefore)
+		}
+	}
+	if eol := endoflife.EOLDate(v); remainingTooShort(eol) {
+		return "--min-remaining", fmt.Errorf("%s %s has less than %s left until EOL on %s", capitalize(name), version, minRemaining, eol)
+	}
+	if failOnSoon && eolWithin(v, soonDays) {
+		return strictFlag("--fail-on-soon"), fmt.Errorf("%s %s reaches EOL within %d days", capitalize(name), version, soonDays)
+	}
+	return "", nil
+}
+
+// checkVerdict decides how checking a single version ends. With --accept
+// only the accepted states pass; otherwise EOL versions fail, and so do
+// those the fail-on flags catch. A version that passed can still end with
+// --warn-exit-code. The condition that failed it is recorded as the
+// result's FailedBy.
+func checkVerdict(name string, version string, v SoftwareVersion, result *Result) error {
+	if len(acceptStates) > 0 {
+		if err := acceptFailure([]Result{*result}); err != nil {
+			result.FailedBy = "--accept"
+			return err
+		}
+		return soonWarning([]Result{*result})
+	}
+	if result.IsEOL {
+		result.FailedBy = failedByEOL
+		return errEOL
+	}
+	if flag, err := policyFailure(name, version, v); err != nil {
+		result.FailedBy = flag
+		return err
+	}
+	return soonWarning([]Result{*result})
+}
+
+// reportMissing prints that a version isn't in the product's data, which only
+// fails the check with --fail-on-missing.
+func reportMissing(name string, version string, provenance Provenance, err error) error {
+	result := errorResult(name, version, provenance, err)
+	var verdict error
+	if failOnMissing {
+		result.FailedBy = "--fail-on-missing"
+		verdict = err
+	}
+	if jsonOutput {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+		return verdict
+	}
+	if verdict == nil {
+		fmt.Printf("%s %s was not found, so it wasn't checked (use --fail-on-missing to fail)\n", capitalize(name), version)
+	}
+	return verdict
+}
 
 // checkCmd represents the check command
 var checkCmd = &cobra.Command{
@@ -121,35 +308,54 @@ var checkCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name, version := args[0], args[1]
+		applyStrict()
+		if failIfEOLBefore != "" {
+			if err := validateDate("fail-if-eol-before", failIfEOLBefore); err != nil {
+				return err
+			}
+		}
 
-		v, err := CheckVersion(name, version)
+		stopSpinner := startSpinner(fmt.Sprintf("Checking %s %s", name, version))
+		v, provenance, err := CheckVersion(cmd.Context(), name, version)
+		stopSpinner()
+		if errors.Is(err, endoflife.ErrVersionNotFound) {
+			return reportMissing(name, version, provenance, err)
+		}
 		if err != nil {
 			return err
 		}
 
-		var supportEndDate string
-		switch supportValue := v.Support.(type) {
-		case string:
-			supportEndDate = supportValue
-		case bool:
-			if !supportValue {
-				supportEndDate = "No Support"
+		result := newResult(name, version, v, provenance)
+		verdict := checkVerdict(name, version, v, &result)
+		if result.FailedBy != "" {
+			logf("%s %s failed by %s", name, version, result.FailedBy)
+		}
+		if jsonOutput {
+			if err := printJSON(result); err != nil {
+				return err
 			}
-		default:
-			supportEndDate = "Unknown"
+			remediate(cmd.Context(), []Result{result}, verdict)
+			return verdict
 		}
 
-		now := time.Now().Format("2006-01-02")
-		if v.EOL > now {
-			if failOnUnsupported {
-				return fmt.Errorf("%s %s is not supported anymore", capitalize(name), version)
-			}
-			fmt.Printf("%s %s is not EOL yet. It will be EOL on %s. Support ends on %s\n", capitalize(name), version, v.EOL, supportEndDate)
-			return nil
-		} else {
-			fmt.Printf("%s %s is EOL since %s. Support ended on: %s\n", capitalize(name), version, v.EOL, supportEndDate)
-			return errors.New("EOL")
+		supportEndDate := endoflife.SupportEndDate(v)
+
+		switch result.Status {
+		case endoflife.StatusEOL:
+			fmt.Printf("%s %s is EOL since %s. Support ended on: %s\n", capitalize(name), version, eolTextWithDistance(v), supportEndDate)
+		case endoflife.StatusMaintenance:
+			fmt.Printf("%s %s is in maintenance mode (security fixes only) until %s. Active support ended on %s\n", capitalize(name), version, eolTextWithDistance(v), supportEndDate)
+		case endoflife.StatusUnknown:
+			fmt.Printf("%s %s has no known EOL date. Support ends on %s\n", capitalize(name), version, supportEndDate)
+		default:
+			fmt.Printf("%s %s is not EOL yet. It will be EOL on %s. Support ends on %s\n", capitalize(name), version, eolTextWithDistance(v), supportEndDate)
+		}
+		printLatestNote(v, version)
+		if result.Snapshot != "" {
+			fmt.Printf("Note: based on embedded data from %s, which may be outdated\n", result.Snapshot)
 		}
+		remediate(cmd.Context(), []Result{result}, verdict)
+		return verdict
 	},
 }
 
@@ -159,6 +365,27 @@ func init() {
 
 	checkCmd.Flags().BoolVarP(&failOnMissing, "fail-on-missing", "m", false, "Fail if the version is not found in the database")
 	checkCmd.Flags().BoolVarP(&failOnUnsupported, "fail-on-unsupported", "u", false, "Fail if the version is not supported by regular updates anymore")
+	checkCmd.Flags().BoolVar(&failOnMaintenance, "fail-on-maintenance", false, "Fail if the version is in maintenance mode (security fixes only)")
+	checkCmd.Flags().BoolVarP(&failOnSoon, "fail-on-soon", "s", false, "Fail if the version reaches EOL within --soon-days")
+	checkCmd.Flags().BoolVar(&failOnUnknown, "fail-on-unknown", false, "Fail if the version has no known EOL date")
+	checkCmd.Flags().BoolVar(&strict, "strict", false, "Fail on anything concerning: same as --fail-on-unsupported --fail-on-soon --fail-on-unknown (alias --fail-on-any)")
+	checkCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "fail-on-any" {
+			name = "strict"
+		}
+		return pflag.NormalizedName(name)
+	})
+	checkCmd.Flags().StringVar(&failIfEOLBefore, "fail-if-eol-before", "", "Fail if the version's EOL date is before this date (YYYY-MM-DD)")
+	checkCmd.Flags().StringSliceVar(&acceptStates, "accept", nil, "Only pass if the version is in one of these states: supported, maintenance, unknown, eol, soon")
+	checkCmd.Flags().IntVar(&soonDays, "soon-days", 30, "Number of days before EOL a version is considered to be EOL soon")
+
+	checkCmd.Flags().BoolVar(&preciseDurations, "precise", false, "Show the time until EOL in days and hours instead of rounded days")
+	addMinRemainingFlag(checkCmd)
+	addRemediationFlag(checkCmd)
+	addJSONFlags(checkCmd, "the result")
 
 	checkChunkCmd.Flags().StringVarP(&tool, "tool", "t", "", "Tool to check versions for")
+	checkChunkCmd.Flags().StringVar(&chunkGroupBy, "group-by", "", "Group the variants in the text output: eol-quarter, by the calendar quarter they reach EOL in")
+	checkChunkCmd.Flags().StringVar(&webhookURL, "webhook", "", "POST one digest of the EOL versions per chunk file to this URL at the end of the run")
+	addJSONFlags(checkChunkCmd, "the report of the variants")
 }
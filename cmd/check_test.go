@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFailOnMissing(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		stdout   string
+		failedBy string
+	}{
+		{"missing version passes", []string{"check", "nodejs", "4"}, 0, "Nodejs 4 was not found, so it wasn't checked", ""},
+		{"fail-on-missing", []string{"check", "-m", "nodejs", "4"}, 1, "", ""},
+		{"json", []string{"check", "--json", "nodejs", "4"}, 0, `"error": "Version not found"`, ""},
+		{"json with fail-on-missing", []string{"check", "--json", "--fail-on-missing", "nodejs", "4"}, 1, `"error": "Version not found"`, "--fail-on-missing"},
+		{"missing product always fails", []string{"check", "--no-embedded", "nosuchproduct", "1"}, 1, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "--retries", "0"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if !strings.Contains(got.stdout, tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", got.stdout, tt.stdout)
+			}
+			if strings.HasPrefix(got.stdout, "{") {
+				var result Result
+				if err := json.Unmarshal([]byte(got.stdout), &result); err != nil {
+					t.Fatal(err)
+				}
+				if result.FailedBy != tt.failedBy {
+					t.Errorf("failedBy = %q, want %q", result.FailedBy, tt.failedBy)
+				}
+			}
+		})
+	}
+}
+
+func TestBehindLatestNote(t *testing.T) {
+	products := testProducts()
+	products["deno"] = `[{"cycle":"2","releaseDate":"2024-10-09","eol":false,"latest":"2.1.4","latestReleaseDate":"2024-12-11"}]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		product string
+		version string
+		note    string
+	}{
+		{"deno", "2.0.0", "Note: 2.0.0 is behind the latest 2 release, 2.1.4 (released 2024-12-11)\n"},
+		{"deno", "2.1.4", ""},
+		{"deno", "2", ""},
+		{"nodejs", "20.1.0", "Note: 20.1.0 is behind the latest 20 release, 20.17.0\n"},
+		{"nodejs", "20.17.0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.product+" "+tt.version, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check", tt.product, tt.version)
+			if got.code != 0 {
+				t.Errorf("exit code = %d, want 0\nstderr: %s", got.code, got.stderr)
+			}
+			_, note, _ := strings.Cut(got.stdout, "\n")
+			if note != tt.note {
+				t.Errorf("note = %q, want %q", note, tt.note)
+			}
+		})
+	}
+}
+
+func TestCheckChunkUnknownTool(t *testing.T) {
+	products := testProducts()
+	products["all"] = `["nodejs","node-red","python","pypy"]`
+	api := newAPIServer(t, products)
+	chunk := writeFile(t, "chunk.yaml", "variants:\n  - name: \"18\"\n  - name: \"22\"\n")
+
+	tests := []struct {
+		tool   string
+		stderr string
+	}{
+		{"nodjs", "Error: Unknown product \"nodjs\", did you mean nodejs?\n"},
+		{"pyton", "Error: Unknown product \"pyton\", did you mean python?\n"},
+		{"kubernetes", "Error: Unknown product \"kubernetes\", run `date-reaper products` to see all of them\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "--no-cache", "check-chunk", "--tool", tt.tool, chunk)
+			if got.code != 1 || got.stdout != "" || got.stderr != tt.stderr {
+				t.Errorf("exit code %d, stdout %q, stderr %q, want exit code 1 and stderr %q", got.code, got.stdout, got.stderr, tt.stderr)
+			}
+			if n := api.count(tt.tool); n != 1 {
+				t.Errorf("%d requests for %s, want one before giving up", n, tt.tool)
+			}
+		})
+	}
+}
+
+func TestFailIfEOLBefore(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	eol := daysFromNow(100)
+
+	tests := []struct {
+		name     string
+		date     string
+		code     int
+		failedBy string
+		stderr   string
+	}{
+		{"eol after the date", daysFromNow(99), 0, "", ""},
+		{"eol on the date", eol, 0, "", ""},
+		{"eol before the date", daysFromNow(101), 1, "--fail-if-eol-before", "Error: Nodejs 22 reaches EOL on " + eol + ", before " + daysFromNow(101) + "\n"},
+		{"historical date", "2024-01-01", 0, "", ""},
+		{"invalid date", "next year", 1, "", "Error: Invalid --fail-if-eol-before \"next year\", expected YYYY-MM-DD\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check", "nodejs", "22", "--json", "--fail-if-eol-before", tt.date)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+			if got.stdout == "" {
+				return
+			}
+			var result Result
+			if err := json.Unmarshal([]byte(got.stdout), &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.FailedBy != tt.failedBy {
+				t.Errorf("failedBy = %q, want %q", result.FailedBy, tt.failedBy)
+			}
+		})
+	}
+}
+
+func TestSupportTrue(t *testing.T) {
+	products := testProducts()
+	products["deno"] = `[
+{"cycle":"2","releaseDate":"2024-10-09","support":true,"eol":"` + daysFromNow(300) + `","latest":"2.1.4"},
+{"cycle":"1","releaseDate":"2020-05-13","support":true,"eol":"2024-10-09","latest":"1.46.3"}
+]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name    string
+		version string
+		code    int
+		line    string
+		support string
+	}{
+		{"eol ahead", "2", 0, "Deno 2 is not EOL yet. It will be EOL on " + daysFromNow(300), "Support ends on " + daysFromNow(300) + "\n"},
+		{"eol passed", "1", 1, "Deno 1 is EOL since 2024-10-09", "Support ended on: 2024-10-09\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check", "deno", tt.version, "--fail-on-unsupported")
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if !strings.HasPrefix(got.stdout, tt.line) || !strings.HasSuffix(got.stdout, tt.support) {
+				t.Errorf("stdout = %q, want it to start with %q and end with %q", got.stdout, tt.line, tt.support)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var ciType string
+
+// gitlabReserved are the top-level .gitlab-ci.yml keys that aren't jobs.
+var gitlabReserved = map[string]bool{
+	"after_script":  true,
+	"before_script": true,
+	"cache":         true,
+	"default":       true,
+	"image":         true,
+	"include":       true,
+	"services":      true,
+	"stages":        true,
+	"variables":     true,
+	"workflow":      true,
+}
+
+type gitlabJob struct {
+	Image     interface{}            `yaml:"image"`
+	Services  []interface{}          `yaml:"services"`
+	Variables map[string]interface{} `yaml:"variables"`
+}
+
+type githubJob struct {
+	Container interface{}            `yaml:"container"`
+	Services  map[string]interface{} `yaml:"services"`
+	Steps     []struct {
+		Uses string `yaml:"uses"`
+	} `yaml:"steps"`
+}
+
+type githubWorkflow struct {
+	Jobs map[string]githubJob `yaml:"jobs"`
+}
+
+// ciImageName returns the image of an image or service entry, which both CI
+// systems accept either as a plain string or as a map with a name (GitLab)
+// or image (GitHub) key.
+func ciImageName(entry interface{}) string {
+	switch entry := entry.(type) {
+	case string:
+		return entry
+	case map[interface{}]interface{}:
+		for _, key := range []string{"name", "image"} {
+			if name, ok := entry[key].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// gitlabVariables flattens a variables block, where values are either plain
+// or a map with a value key.
+func gitlabVariables(vars map[string]interface{}, into map[string]string) {
+	for name, value := range vars {
+		if m, ok := value.(map[interface{}]interface{}); ok {
+			value = m["value"]
+		}
+		if value != nil {
+			into[name] = fmt.Sprint(value)
+		}
+	}
+}
+
+// gitlabImages extracts the images of a .gitlab-ci.yml, expanding variables
+// from its variables blocks and the environment.
+func gitlabImages(data []byte) ([]imageRef, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var global gitlabJob
+	if err := yaml.Unmarshal(data, &global); err != nil {
+		return nil, err
+	}
+	globalVars := map[string]string{}
+	gitlabVariables(global.Variables, globalVars)
+
+	var defaults gitlabJob
+	if raw, ok := doc["default"]; ok {
+		if err := remarshal(raw, &defaults); err != nil {
+			return nil, fmt.Errorf("default: %s", err)
+		}
+	}
+
+	var refs []imageRef
+	add := func(source string, entry interface{}, vars map[string]string) {
+		image := ciImageName(entry)
+		if image == "" {
+			return
+		}
+		image, _ = interpolate(image, func(name string) (string, bool) {
+			if value, ok := vars[name]; ok {
+				return value, true
+			}
+			return os.LookupEnv(name)
+		})
+		refs = append(refs, imageRef{Source: source, Image: image})
+	}
+	addJob := func(name string, job gitlabJob, vars map[string]string) {
+		add(name, job.Image, vars)
+		for _, service := range job.Services {
+			add(name+" (service)", service, vars)
+		}
+	}
+
+	addJob("default", global, globalVars)
+	addJob("default", defaults, globalVars)
+	for _, name := range sortedKeys(doc) {
+		if gitlabReserved[name] || strings.HasPrefix(name, ".") {
+			continue
+		}
+		var job gitlabJob
+		if err := remarshal(doc[name], &job); err != nil {
+			continue
+		}
+		vars := map[string]string{}
+		for k, v := range globalVars {
+			vars[k] = v
+		}
+		gitlabVariables(job.Variables, vars)
+		addJob(name, job, vars)
+	}
+	return refs, nil
+}
+
+// githubImages extracts the container, service and docker:// step images of
+// a GitHub Actions workflow.
+func githubImages(data []byte) ([]imageRef, error) {
+	var workflow githubWorkflow
+	if err := yaml.Unmarshal(data, &workflow); err != nil {
+		return nil, err
+	}
+
+	var refs []imageRef
+	for _, name := range sortedKeys(workflow.Jobs) {
+		job := workflow.Jobs[name]
+		if image := ciImageName(job.Container); image != "" {
+			refs = append(refs, imageRef{Source: name, Image: image})
+		}
+		for _, service := range sortedKeys(job.Services) {
+			if image := ciImageName(job.Services[service]); image != "" {
+				refs = append(refs, imageRef{Source: name + " (service " + service + ")", Image: image})
+			}
+		}
+		for i, step := range job.Steps {
+			if image, ok := strings.CutPrefix(step.Uses, "docker://"); ok {
+				refs = append(refs, imageRef{Source: fmt.Sprintf("%s (step %d)", name, i+1), Image: image})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// remarshal converts a generically decoded YAML value into a typed one.
+func remarshal(in interface{}, out interface{}) error {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// detectCIType guesses whether a file is a GitLab CI or a GitHub Actions
+// definition, from its name first and its jobs block second.
+func detectCIType(path string, data []byte) string {
+	if filepath.Base(path) == ".gitlab-ci.yml" {
+		return "gitlab"
+	}
+	if strings.Contains(filepath.ToSlash(path), ".github/workflows/") {
+		return "github"
+	}
+	var workflow githubWorkflow
+	if err := yaml.Unmarshal(data, &workflow); err == nil && len(workflow.Jobs) > 0 {
+		return "github"
+	}
+	return "gitlab"
+}
+
+// checkCICmd represents the check-ci command
+var checkCICmd = &cobra.Command{
+	Use:         "check-ci <path-to-ci-file>",
+	Annotations: map[string]string{inputsAnnotation: ".gitlab-ci.yml,github-workflow"},
+	Short:       "Check the images used by a GitLab CI or GitHub Actions file for EOL versions",
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Error reading CI file: %s", err)
+		}
+
+		kind := ciType
+		if kind == "auto" {
+			kind = detectCIType(path, data)
+		}
+
+		var refs []imageRef
+		switch kind {
+		case "gitlab":
+			refs, err = gitlabImages(data)
+		case "github":
+			refs, err = githubImages(data)
+		default:
+			return fmt.Errorf("Unknown CI type %q, expected auto, gitlab or github", ciType)
+		}
+		if err != nil {
+			return fmt.Errorf("Error parsing YAML: %s", err)
+		}
+
+		return checkImages(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCICmd)
+
+	checkCICmd.Flags().StringVar(&ciType, "type", "auto", "CI system: auto, gitlab or github")
+	addMinRemainingFlag(checkCICmd)
+	addRemediationFlag(checkCICmd)
+	addJSONFlags(checkCICmd, "the report of the images")
+	addPlanFlag(checkCICmd)
+}
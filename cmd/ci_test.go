@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const gitlabFixture = `image: node:${NODE_VERSION}
+variables:
+  NODE_VERSION: "20"
+  PY:
+    value: "3.12"
+    description: Python to test with
+default:
+  services:
+    - redis:7
+stages: [test]
+.template:
+  image: ruby:3.1
+test:
+  image:
+    name: python:${PY}-slim
+    entrypoint: [""]
+  services:
+    - name: postgres:15
+      alias: db
+legacy:
+  variables:
+    NODE_VERSION: "18"
+  image: node:$NODE_VERSION-alpine
+`
+
+const githubFixture = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container: node:20
+    services:
+      redis:
+        image: redis:7
+      db:
+        image: postgres:15
+    steps:
+      - uses: actions/checkout@v4
+      - uses: docker://python:3.12
+  lint:
+    runs-on: ubuntu-latest
+    container:
+      image: golang:1.22
+`
+
+func TestGitlabImages(t *testing.T) {
+	got, err := gitlabImages([]byte(gitlabFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []imageRef{
+		{Source: "default", Image: "node:20"},
+		{Source: "default (service)", Image: "redis:7"},
+		{Source: "legacy", Image: "node:18-alpine"},
+		{Source: "test", Image: "python:3.12-slim"},
+		{Source: "test (service)", Image: "postgres:15"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestGithubImages(t *testing.T) {
+	got, err := githubImages([]byte(githubFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []imageRef{
+		{Source: "lint", Image: "golang:1.22"},
+		{Source: "test", Image: "node:20"},
+		{Source: "test (service db)", Image: "postgres:15"},
+		{Source: "test (service redis)", Image: "redis:7"},
+		{Source: "test (step 2)", Image: "python:3.12"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDetectCIType(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want string
+	}{
+		{".gitlab-ci.yml", githubFixture, "gitlab"},
+		{"repo/.github/workflows/ci.yml", gitlabFixture, "github"},
+		{"ci.yml", githubFixture, "github"},
+		{"ci.yml", gitlabFixture, "gitlab"},
+	}
+	for _, tt := range tests {
+		if got := detectCIType(tt.path, []byte(tt.data)); got != tt.want {
+			t.Errorf("detectCIType(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCI(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	workflows := filepath.Join(t.TempDir(), ".github", "workflows")
+	if err := os.MkdirAll(workflows, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	workflow := filepath.Join(workflows, "ci.yml")
+	if err := os.WriteFile(workflow, []byte("jobs:\n  test:\n    container: node:18\n    steps:\n      - uses: docker://python:3.12\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := run(t, nil, "--api-url", api.apiURL(), "check-ci", workflow)
+	want := strings.Join([]string{
+		"test: Nodejs 18 is EOL since 2025-04-30",
+		"test (step 1): Python 3.12 is in maintenance mode until " + daysFromNow(1500),
+	}, "\n") + "\n"
+	if got.code != 1 || got.stdout != want {
+		t.Errorf("exit code %d, stdout %q, want exit code 1 and stdout %q\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+
+	got = run(t, nil, "--api-url", api.apiURL(), "check-ci", "--type", "jenkins", workflow)
+	if want := "Error: Unknown CI type \"jenkins\", expected auto, gitlab or github\n"; got.code != 1 || got.stderr != want {
+		t.Errorf("exit code %d, stderr %q, want %q", got.code, got.stderr, want)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type composeService struct {
+	Image string      `yaml:"image"`
+	Build interface{} `yaml:"build"`
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+var composeEnv []string
+
+var composeVarPattern = regexp.MustCompile(`\$(\$|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// interpolate expands variables the way docker compose does: $VAR, ${VAR},
+// ${VAR:-default}, ${VAR-default}, ${VAR:?error}, ${VAR?error} and $$ for a
+// literal dollar sign.
+func interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	var err error
+	expanded := composeVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		m := composeVarPattern.FindStringSubmatch(match)
+		if m[1] == "$" {
+			return "$"
+		}
+		name, op, arg := m[2], m[3], m[4]
+		if name == "" {
+			name = m[5]
+		}
+		val, set := lookup(name)
+		switch op {
+		case ":-":
+			if val == "" {
+				return arg
+			}
+		case "-":
+			if !set {
+				return arg
+			}
+		case ":?", "?":
+			if !set || (op == ":?" && val == "") {
+				err = fmt.Errorf("required variable %s is missing a value: %s", name, arg)
+			}
+		}
+		return val
+	})
+	return expanded, err
+}
+
+// readEnvFile reads KEY=VALUE lines from a compose .env file.
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+// composeLookup resolves variables from --env, then the environment, then the
+// .env file next to the compose file.
+func composeLookup(composePath string) (func(string) (string, bool), error) {
+	dotEnv, err := readEnvFile(filepath.Join(filepath.Dir(composePath), ".env"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Error reading .env file: %s", err)
+	}
+	overrides := map[string]string{}
+	for _, kv := range composeEnv {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("Invalid --env value %q, expected KEY=VALUE", kv)
+		}
+		overrides[key] = value
+	}
+
+	return func(name string) (string, bool) {
+		if value, ok := overrides[name]; ok {
+			return value, true
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := dotEnv[name]
+		return value, ok
+	}, nil
+}
+
+// checkComposeCmd represents the check-compose command
+var checkComposeCmd = &cobra.Command{
+	Use:         "check-compose <path-to-docker-compose.yml>",
+	Annotations: map[string]string{inputsAnnotation: "docker-compose.yml"},
+	Short:       "Check the images of a docker-compose.yml for EOL versions",
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		composePath := args[0]
+		data, err := os.ReadFile(composePath)
+		if err != nil {
+			return fmt.Errorf("Error reading compose file: %s", err)
+		}
+
+		var compose composeFile
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			return fmt.Errorf("Error parsing YAML: %s", err)
+		}
+
+		lookup, err := composeLookup(composePath)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(compose.Services))
+		for name := range compose.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var refs []imageRef
+		for _, name := range names {
+			service := compose.Services[name]
+			if service.Build != nil {
+				refs = append(refs, imageRef{Source: name, Skip: "(the image is built from source)"})
+				continue
+			}
+			if service.Image == "" {
+				continue
+			}
+
+			image, err := interpolate(service.Image, lookup)
+			if err != nil {
+				return fmt.Errorf("Error in service %s: %s", name, err)
+			}
+			refs = append(refs, imageRef{Source: name, Image: image})
+		}
+
+		return checkImages(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkComposeCmd)
+
+	checkComposeCmd.Flags().StringArrayVarP(&composeEnv, "env", "e", nil, "Set a variable for interpolation (KEY=VALUE, repeatable)")
+	addMinRemainingFlag(checkComposeCmd)
+	addRemediationFlag(checkComposeCmd)
+	addJSONFlags(checkComposeCmd, "the report of the images")
+	addPlanFlag(checkComposeCmd)
+}
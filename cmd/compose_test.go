@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckCompose(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "docker-compose.yml")
+	if err := os.WriteFile(compose, []byte(`services:
+  web:
+    image: node:${NODE:-18}-alpine
+  app:
+    build: .
+  db:
+    image: docker.io/library/python:${PY}
+  cache:
+    image: ghcr.io/acme/cache:1
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PY=3.12\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout []string
+	}{
+		{"defaults and .env", nil, 1, []string{
+			"app: skipped (the image is built from source)",
+			"cache: skipped ghcr.io/acme/cache:1, acme/cache isn't a known image",
+			"db: Python 3.12 is in maintenance mode until " + daysFromNow(1500),
+			"web: Nodejs 18 is EOL since 2025-04-30",
+		}},
+		{"--env overrides", []string{"-e", "NODE=20", "-e", "PY=3.9"}, 1, []string{
+			"app: skipped (the image is built from source)",
+			"cache: skipped ghcr.io/acme/cache:1, acme/cache isn't a known image",
+			"db: Python 3.9 is EOL since 2025-10-31",
+			"web: Nodejs 20 is in maintenance mode until " + daysFromNow(2000),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "check-compose"}, tt.args...)
+			got := run(t, nil, append(args, compose)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if want := strings.Join(tt.stdout, "\n") + "\n"; got.stdout != want {
+				t.Errorf("stdout = %q, want %q", got.stdout, want)
+			}
+		})
+	}
+}
+
+func TestCheckComposeRequiredVariable(t *testing.T) {
+	compose := writeFile(t, "docker-compose.yml", "services:\n  web:\n    image: node:${NODE:?set the node version}\n")
+	got := run(t, nil, "--no-cache", "check-compose", compose)
+	if want := "Error: Error in service web: required variable NODE is missing a value: set the node version\n"; got.code == 0 || got.stderr != want {
+		t.Errorf("exit code %d, stderr = %q, want %q", got.code, got.stderr, want)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+var configPath string
+var profile string
+
+// Config is the optional YAML config file. Profiles map a name to a set of
+// flag values, e.g.
+//
+//	profiles:
+//	  prod:
+//	    fail-on-unsupported: true
+//	    fail-on-soon: true
+//	    soon-days: 90
+//	  dev: {}
+type Config struct {
+	Profiles map[string]map[string]interface{} `yaml:"profiles"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "date-reaper", "config.yaml")
+}
+
+// loadConfig reads the config file. A missing file at the default location
+// is not an error, but an explicitly given --config has to exist.
+func loadConfig(cmd *cobra.Command) (Config, error) {
+	var config Config
+	path := configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !cmd.Flags().Changed("config") {
+			return config, nil
+		}
+		return config, fmt.Errorf("Error reading config file: %s", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("Error parsing config file %s: %s", path, err)
+	}
+	return config, nil
+}
+
+// applyProfile sets the flags of the selected profile on cmd. Flags given on
+// the command line take precedence, and flags the command doesn't have are
+// skipped so one profile can be shared between commands.
+func applyProfile(cmd *cobra.Command) error {
+	if profile == "" {
+		return nil
+	}
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	values, ok := config.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("Profile %q is not defined in the config file", profile)
+	}
+
+	for name, value := range values {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := setFlag(cmd.Flags(), flag, value); err != nil {
+			return fmt.Errorf("Error applying profile %s: %s", profile, err)
+		}
+	}
+	return nil
+}
+
+func setFlag(flags *pflag.FlagSet, flag *pflag.Flag, value interface{}) error {
+	if err := flags.Set(flag.Name, fmt.Sprint(value)); err != nil {
+		return fmt.Errorf("invalid value %v for --%s", value, flag.Name)
+	}
+	return nil
+}
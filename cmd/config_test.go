@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfileValuesAreValidated(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	config := writeFile(t, "config.yaml", `profiles:
+  short: {min-remaining: 1y}
+  long: {min-remaining: 7d}
+  badaccept: {accept: bogus}
+  badwarn: {warn-exit-code: 1}
+  badgranularity: {match-granularity: bogus}
+  badremaining: {min-remaining: soon}
+`)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"min-remaining from profile", []string{"--profile", "short"}, 1, "has less than 1y left until EOL"},
+		{"min-remaining on command line", []string{"--min-remaining", "1y"}, 1, "has less than 1y left until EOL"},
+		{"min-remaining met", []string{"--profile", "long"}, 0, ""},
+		{"command line wins over profile", []string{"--profile", "short", "--min-remaining", "7d"}, 0, ""},
+		{"accept", []string{"--profile", "badaccept"}, 1, `Invalid --accept state "bogus"`},
+		{"warn-exit-code", []string{"--profile", "badwarn"}, 1, "Invalid --warn-exit-code 1"},
+		{"match-granularity", []string{"--profile", "badgranularity"}, 1, `Invalid --match-granularity "bogus"`},
+		{"unparseable min-remaining", []string{"--profile", "badremaining"}, 1, `Invalid --min-remaining "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--config", config, "--api-url", api.apiURL(), "check", "nodejs", "22"}, tt.args...)
+			got := run(t, nil, args...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr != "" && !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	config := writeFile(t, "config.yaml", `profiles:
+  prod:
+    fail-on-unsupported: true
+    soon-days: 90
+  ci:
+    fail-on-soon: true
+    soon-days: 120
+    merge-duplicate-products: true
+  dev: {}
+`)
+
+	// nodejs 20 is past its support date and 22 reaches EOL in about 100
+	// days.
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"prod fails unsupported versions", []string{"--profile", "prod", "check", "nodejs", "20"}, 1, "Error: Nodejs 20 is not supported anymore\n"},
+		{"prod's soon window is too short to matter", []string{"--profile", "prod", "check", "nodejs", "22", "--fail-on-soon"}, 0, ""},
+		{"ci fails versions reaching EOL soon", []string{"--profile", "ci", "check", "nodejs", "22"}, 1, "Error: Nodejs 22 reaches EOL within 120 days\n"},
+		{"command line overrides the profile", []string{"--profile", "ci", "check", "nodejs", "22", "--soon-days", "30"}, 0, ""},
+		{"flags the command lacks are skipped", []string{"--profile", "ci", "check-pairs", "nodejs", "20"}, 0, ""},
+		{"dev passes", []string{"--profile", "dev", "check", "nodejs", "20"}, 0, ""},
+		{"no profile", []string{"check", "nodejs", "20"}, 0, ""},
+		{"unknown profile", []string{"--profile", "staging", "check", "nodejs", "20"}, 1, "Error: Profile \"staging\" is not defined in the config file\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--config", config, "--api-url", api.apiURL()}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// conflictingFlags are pairs of flags that can't be combined, with the
+// reason shown to the user.
+var conflictingFlags = []struct {
+	a, b   string
+	reason string
+}{
+	{"no-cache", "refresh", "--refresh falls back to cached responses, which --no-cache never reads"},
+	{"network-only", "no-cache", "--network-only already ignores the cache"},
+	{"network-only", "refresh", "--network-only never falls back to cached responses"},
+	{"json", "format", "--json is short for --format json"},
+}
+
+// checkConflicts fails before any work is done if the command line combines
+// flags that contradict each other.
+func checkConflicts(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	for _, c := range conflictingFlags {
+		a, b := flags.Lookup(c.a), flags.Lookup(c.b)
+		if a != nil && b != nil && a.Changed && b.Changed {
+			return fmt.Errorf("--%s and --%s can't be used together: %s", c.a, c.b, c.reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import "testing"
+
+func TestConflictingFlags(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n")
+
+	tests := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{"--no-cache and --refresh", []string{"--no-cache", "--refresh", "check", "nodejs", "22"},
+			"Error: --no-cache and --refresh can't be used together: --refresh falls back to cached responses, which --no-cache never reads\n"},
+		{"--network-only and --no-cache", []string{"--network-only", "--no-cache", "check", "nodejs", "22"},
+			"Error: --network-only and --no-cache can't be used together: --network-only already ignores the cache\n"},
+		{"--network-only and --refresh", []string{"check", "nodejs", "22", "--refresh", "--network-only"},
+			"Error: --network-only and --refresh can't be used together: --network-only never falls back to cached responses\n"},
+		{"--json and --format", []string{"check-inventory", "--json", "--format", "table", inventory},
+			"Error: --json and --format can't be used together: --json is short for --format json\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			got := run(t, nil, append([]string{"--api-url", api.apiURL()}, tt.args...)...)
+			if got.code != 1 || got.stdout != "" || got.stderr != tt.stderr {
+				t.Errorf("exit code %d, stdout %q, stderr %q, want exit code 1 and stderr %q", got.code, got.stdout, got.stderr, tt.stderr)
+			}
+			if n := api.count("nodejs"); n != 0 {
+				t.Errorf("nodejs was fetched %d times, want no work done", n)
+			}
+		})
+	}
+
+	api := newAPIServer(t, testProducts())
+	if got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", "--format", "table", inventory); got.code != 0 {
+		t.Errorf("--format alone: exit code = %d\nstderr: %s", got.code, got.stderr)
+	}
+}
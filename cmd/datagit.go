@@ -0,0 +1,75 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// With --data-git-dir, product data is read from a local git clone that holds
+// API responses as api/<name>.json, at --data-git-ref (HEAD by default),
+// instead of from the API. Pinning the ref to a commit makes an audit
+// reproducible byte for byte. The cache isn't used, since a commit never
+// changes.
+var dataGitDir string
+var dataGitRef string
+
+// validateDataGit makes sure --data-git-ref comes with a clone to read from.
+func validateDataGit() error {
+	if dataGitRef != "" && dataGitDir == "" {
+		return errors.New("--data-git-ref needs --data-git-dir, the clone to read the data from")
+	}
+	return nil
+}
+
+// gitOutput runs git in the --data-git-dir clone and returns its stdout.
+func gitOutput(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dataGitDir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], err)
+	}
+	return out, nil
+}
+
+// fetchProductFromGit reads a product's data from the --data-git-dir clone.
+// A product missing at the ref is reported like the API's 404, so callers
+// can suggest similar products.
+func fetchProductFromGit(ctx context.Context, name string) ([]byte, Provenance, error) {
+	ref := dataGitRef
+	if ref == "" {
+		ref = "HEAD"
+	}
+	object := ref + ":" + filepath.ToSlash(filepath.Join("api", filepath.Base(name)+".json"))
+	provenance := Provenance{URL: "git:" + dataGitDir + "@" + object, FetchedAt: time.Now()}
+
+	logf("git show %s", object)
+	if _, err := gitOutput(ctx, "cat-file", "-e", object); err != nil {
+		if _, refErr := gitOutput(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); refErr != nil {
+			return nil, provenance, fmt.Errorf("Error reading %s: unknown git ref %q", dataGitDir, ref)
+		}
+		provenance.Status = http.StatusNotFound
+		return nil, provenance, fmt.Errorf("Error: %s doesn't exist at %s", object, ref)
+	}
+	body, err := gitOutput(ctx, "show", object)
+	if err != nil {
+		return nil, provenance, err
+	}
+	provenance.Status = http.StatusOK
+	return body, provenance, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// gitRepo makes a git repository whose commits each write the given files,
+// tagging commit i as v<i+1>.
+func gitRepo(t *testing.T, commits ...map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n%s", args[0], err, out)
+		}
+	}
+	git("init", "-q")
+	for i, files := range commits {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "data")
+		git("tag", "v"+strconv.Itoa(i+1))
+	}
+	return dir
+}
+
+func TestDataGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := gitRepo(t,
+		map[string]string{"api/nodejs.json": `[{"cycle":"22","releaseDate":"2024-04-24","eol":"` + daysFromNow(100) + `"}]`},
+		map[string]string{
+			"api/nodejs.json": `[{"cycle":"22","releaseDate":"2024-04-24","eol":"2024-05-01"}]`,
+			"api/python.json": testProducts()["python"],
+		},
+	)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"HEAD by default", []string{"--data-git-dir", repo, "check", "nodejs", "22"}, 1, "is EOL since 2024-05-01", ""},
+		{"tag", []string{"--data-git-dir", repo, "--data-git-ref", "v1", "check", "nodejs", "22"}, 0, "is not EOL yet", ""},
+		{"relative ref", []string{"--data-git-dir", repo, "--data-git-ref", "HEAD~1", "check", "nodejs", "22"}, 0, "is not EOL yet", ""},
+		{"product added later", []string{"--data-git-dir", repo, "--data-git-ref", "v1", "check", "python", "3.12"}, 1, "", "api/python.json doesn't exist at v1"},
+		{"unknown ref", []string{"--data-git-dir", repo, "--data-git-ref", "v9", "check", "nodejs", "22"}, 1, "", `unknown git ref "v9"`},
+		{"ref without a clone", []string{"--data-git-ref", "v1", "check", "nodejs", "22"}, 1, "", "--data-git-ref needs --data-git-dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "--no-embedded"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if !strings.Contains(got.stdout, tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", got.stdout, tt.stdout)
+			}
+			if !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+			if n := api.count("nodejs") + api.count("python"); n != 0 {
+				t.Errorf("%d API request(s), want none", n)
+			}
+		})
+	}
+}
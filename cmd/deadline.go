@@ -0,0 +1,51 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var deadline time.Duration
+
+// cancelDeadline releases the --deadline timer; see stopDeadline.
+var cancelDeadline context.CancelFunc
+
+// startDeadline bounds the whole run to --deadline by giving the command a
+// context that cancels any in-flight requests once it passes.
+func startDeadline(cmd *cobra.Command) {
+	if deadline <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), deadline)
+	cancelDeadline = cancel
+	cmd.SetContext(ctx)
+}
+
+// stopDeadline releases the --deadline timer once the command has returned.
+func stopDeadline() {
+	if cancelDeadline != nil {
+		cancelDeadline()
+		cancelDeadline = nil
+	}
+}
+
+// incompleteError reports a bulk run that stopped before every item was
+// checked.
+func incompleteError(ctx context.Context, checked int, total int) error {
+	reason := "run was cancelled"
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		reason = "deadline exceeded"
+	}
+	return &exitError{
+		code: exitIncomplete,
+		err:  fmt.Errorf("Incomplete results, %s after checking %d of %d items", reason, checked, total),
+	}
+}
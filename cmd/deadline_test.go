@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDeadline(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: python\n  version: \"3.12\"\n")
+
+	tests := []struct {
+		name     string
+		slow     bool
+		deadline string
+		code     int
+		stderr   string
+	}{
+		{"fast run", false, "10s", 0, ""},
+		{"slow product", true, "300ms", 3, "Incomplete results, deadline exceeded after checking 1 of 2 items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			if tt.slow {
+				api.slow("python", 0, 10*time.Second)
+			}
+			start := time.Now()
+			got := run(t, nil, "--api-url", api.apiURL(), "--deadline", tt.deadline, "check-inventory", "-c", "1", inventory)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("run took %s, want the deadline to stop it", elapsed)
+			}
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if !strings.Contains(got.stderr, tt.stderr) || strings.Contains(got.stderr, "Usage:") {
+				t.Errorf("stderr = %q, want it to contain %q and no usage", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestDeadlinePartialJSON(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"20\"\n- product: python\n  version: \"3.12\"\n")
+	api := newAPIServer(t, testProducts())
+	api.slow("python", 0, 10*time.Second)
+
+	got := run(t, nil, "--api-url", api.apiURL(), "--deadline", "300ms", "check-inventory", "--json", "-c", "1", inventory)
+	if got.code != exitIncomplete {
+		t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, exitIncomplete, got.stderr)
+	}
+	want := `{
+  "ok": false,
+  "exitCode": 3,
+  "incomplete": true,
+  "results": [
+    {
+      "product": "nodejs",
+      "version": "20",
+      "eol": "` + daysFromNow(2000) + `",
+      "support": "2024-10-22",
+      "status": "maintenance",
+      "isEol": false
+    }
+  ]
+}
+`
+	if got.stdout != want {
+		t.Errorf("stdout = %s, want %s", got.stdout, want)
+	}
+	if want := "Error: Incomplete results, deadline exceeded after checking 1 of 2 items\n"; got.stderr != want {
+		t.Errorf("stderr = %q, want %q", got.stderr, want)
+	}
+}
+
+func TestStopDeadline(t *testing.T) {
+	savedDeadline := deadline
+	t.Cleanup(func() { deadline = savedDeadline })
+	deadline = time.Hour
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	startDeadline(cmd)
+	if cmd.Context().Err() != nil {
+		t.Fatal("the deadline context is done before the run")
+	}
+	stopDeadline()
+	if !errors.Is(cmd.Context().Err(), context.Canceled) {
+		t.Errorf("context error = %v after stopDeadline, want it cancelled", cmd.Context().Err())
+	}
+	stopDeadline()
+}
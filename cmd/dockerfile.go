@@ -0,0 +1,188 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var buildArgs []string
+
+// baseImage is a unique base image of a Dockerfile, with every line and
+// platform it is used for.
+type baseImage struct {
+	image     string
+	lines     []int
+	platforms []string
+}
+
+func (b baseImage) source() string {
+	lines := make([]string, len(b.lines))
+	for i, line := range b.lines {
+		lines[i] = fmt.Sprint(line)
+	}
+	source := "line " + strings.Join(lines, ", ")
+	if len(b.lines) > 1 {
+		source = "lines " + strings.Join(lines, ", ")
+	}
+	if len(b.platforms) > 0 {
+		source += " (" + strings.Join(b.platforms, ", ") + ")"
+	}
+	return source
+}
+
+// dockerfileLines joins continuation lines, returning each instruction with
+// the line number it starts on. Like Docker, comment lines within a
+// continued instruction are dropped.
+func dockerfileLines(data []byte) ([]string, []int, error) {
+	var instructions []string
+	var numbers []int
+	var current strings.Builder
+	start := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if current.Len() == 0 {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			start = n
+		} else if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\") + " ")
+			continue
+		}
+		current.WriteString(line)
+		instructions = append(instructions, current.String())
+		numbers = append(numbers, start)
+		current.Reset()
+	}
+	return instructions, numbers, scanner.Err()
+}
+
+// dockerfileBaseImages extracts the unique base images of a Dockerfile,
+// skipping scratch and references to earlier build stages. ARGs declared
+// before a FROM (or given with --build-arg) are expanded, so the same image
+// used on several platforms of a build matrix is only reported once.
+func dockerfileBaseImages(data []byte, args map[string]string) ([]baseImage, error) {
+	instructions, numbers, err := dockerfileLines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	stages := map[string]bool{}
+	index := map[string]int{}
+	var images []baseImage
+	lookup := func(name string) (string, bool) {
+		if value, ok := args[name]; ok {
+			return value, true
+		}
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	for i, instruction := range instructions {
+		fields := strings.Fields(instruction)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			for _, arg := range fields[1:] {
+				name, value, _ := strings.Cut(arg, "=")
+				if _, ok := vars[name]; !ok {
+					vars[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			var platform string
+			rest := fields[1:]
+			for len(rest) > 0 && strings.HasPrefix(rest[0], "--") {
+				if value, ok := strings.CutPrefix(rest[0], "--platform="); ok {
+					platform, _ = interpolate(value, lookup)
+				}
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return nil, fmt.Errorf("line %d: FROM without an image", numbers[i])
+			}
+			ref, _ := interpolate(rest[0], lookup)
+			isStage := stages[strings.ToLower(ref)]
+			if len(rest) >= 3 && strings.EqualFold(rest[1], "AS") {
+				stages[strings.ToLower(rest[2])] = true
+			}
+			if ref == "scratch" || isStage {
+				continue
+			}
+
+			j, ok := index[ref]
+			if !ok {
+				j = len(images)
+				index[ref] = j
+				images = append(images, baseImage{image: ref})
+			}
+			images[j].lines = append(images[j].lines, numbers[i])
+			if platform != "" && !slices.Contains(images[j].platforms, platform) {
+				images[j].platforms = append(images[j].platforms, platform)
+			}
+		}
+	}
+	return images, nil
+}
+
+// checkDockerfileCmd represents the check-dockerfile command
+var checkDockerfileCmd = &cobra.Command{
+	Use:         "check-dockerfile <path-to-Dockerfile>",
+	Annotations: map[string]string{inputsAnnotation: "Dockerfile"},
+	Short:       "Check the base images of a Dockerfile for EOL versions",
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("Error reading Dockerfile: %s", err)
+		}
+
+		overrides := map[string]string{}
+		for _, kv := range buildArgs {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				return fmt.Errorf("Invalid --build-arg value %q, expected KEY=VALUE", kv)
+			}
+			overrides[key] = value
+		}
+
+		images, err := dockerfileBaseImages(data, overrides)
+		if err != nil {
+			return fmt.Errorf("Error parsing Dockerfile: %s", err)
+		}
+
+		refs := make([]imageRef, len(images))
+		for i, image := range images {
+			refs[i] = imageRef{Source: image.source(), Image: image.image}
+		}
+		return checkImages(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkDockerfileCmd)
+
+	checkDockerfileCmd.Flags().StringArrayVar(&buildArgs, "build-arg", nil, "Set a build-time variable (KEY=VALUE, repeatable)")
+	addMinRemainingFlag(checkDockerfileCmd)
+	addRemediationFlag(checkDockerfileCmd)
+	addJSONFlags(checkDockerfileCmd, "the report of the base images")
+	addPlanFlag(checkDockerfileCmd)
+}
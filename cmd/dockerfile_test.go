@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDockerfileBaseImages(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		args       map[string]string
+		want       []baseImage
+	}{
+		{
+			name:       "single stage",
+			dockerfile: "FROM node:18\nRUN npm ci\n",
+			want:       []baseImage{{image: "node:18", lines: []int{1}}},
+		},
+		{
+			name:       "stages and scratch are skipped",
+			dockerfile: "FROM golang:1.22 AS build\nFROM build AS test\nFROM scratch\nCOPY --from=build /app /app\n",
+			want:       []baseImage{{image: "golang:1.22", lines: []int{1}}},
+		},
+		{
+			name:       "whitespace-only continued instruction",
+			dockerfile: "FROM node:18 AS a\n\\\n\nFROM node:20\n",
+			want:       []baseImage{{image: "node:18", lines: []int{1}}, {image: "node:20", lines: []int{4}}},
+		},
+		{
+			name:       "comment inside a continuation",
+			dockerfile: "FROM \\\n# the runtime\n  node:20 \\\n  # its stage name\n  AS runtime\nFROM runtime\n",
+			want:       []baseImage{{image: "node:20", lines: []int{1}}},
+		},
+		{
+			name:       "args and platforms",
+			dockerfile: "ARG NODE=18\nFROM --platform=linux/amd64 node:${NODE}\nFROM --platform=linux/arm64 node:${NODE}\n",
+			want:       []baseImage{{image: "node:18", lines: []int{2, 3}, platforms: []string{"linux/amd64", "linux/arm64"}}},
+		},
+		{
+			name:       "build args override",
+			dockerfile: "ARG NODE=18\nFROM node:${NODE}\n",
+			args:       map[string]string{"NODE": "20"},
+			want:       []baseImage{{image: "node:20", lines: []int{2}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dockerfileBaseImages([]byte(tt.dockerfile), tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockerfileFromWithoutImage(t *testing.T) {
+	if _, err := dockerfileBaseImages([]byte("FROM --platform=linux/amd64\n"), nil); err == nil {
+		t.Error("expected an error for FROM without an image")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// diagnosis is the outcome of one doctor check.
+type diagnosis struct {
+	name   string
+	ok     bool
+	detail string
+}
+
+// lookupFlag finds the first flag with this name on the root command or any
+// other command, with the flag set it belongs to. A profile is shared between
+// commands, so that is all its flags can be checked against.
+func lookupFlag(name string) (*pflag.FlagSet, *pflag.Flag) {
+	if flag := rootCmd.PersistentFlags().Lookup(name); flag != nil {
+		return rootCmd.PersistentFlags(), flag
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if flag := cmd.Flags().Lookup(name); flag != nil {
+			return cmd.Flags(), flag
+		}
+	}
+	return nil, nil
+}
+
+func knownFlag(name string) bool {
+	_, flag := lookupFlag(name)
+	return flag != nil
+}
+
+// checkProfileValues sets a profile's values on their flags and validates
+// them like a run with the profile would, then puts the flags back as they
+// were.
+func checkProfileValues(values map[string]interface{}) error {
+	type saved struct {
+		flag    *pflag.Flag
+		value   string
+		slice   []string
+		changed bool
+	}
+	var restore []saved
+	defer func() {
+		for i := len(restore) - 1; i >= 0; i-- {
+			r := restore[i]
+			if slice, ok := r.flag.Value.(pflag.SliceValue); ok {
+				slice.Replace(r.slice)
+			} else {
+				r.flag.Value.Set(r.value)
+			}
+			r.flag.Changed = r.changed
+		}
+		// Derive the settings from the restored flags again.
+		validateFlags()
+	}()
+
+	for _, name := range sortedKeys(values) {
+		flags, flag := lookupFlag(name)
+		if flag == nil {
+			continue
+		}
+		r := saved{flag: flag, value: flag.Value.String(), changed: flag.Changed}
+		// Empty slices first, so the profile's values replace them rather
+		// than being appended.
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			r.slice = slice.GetSlice()
+			slice.Replace(nil)
+		}
+		restore = append(restore, r)
+		if err := setFlag(flags, flag, values[name]); err != nil {
+			return err
+		}
+	}
+	return validateFlags()
+}
+
+func diagnoseConfig(cmd *cobra.Command) []diagnosis {
+	path := configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return []diagnosis{{"config file", false, err.Error()}}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return []diagnosis{{"config file", true, fmt.Sprintf("none at %s (it's optional)", path)}}
+	}
+
+	results := []diagnosis{{"config file", true, fmt.Sprintf("%s, %d profile(s)", path, len(config.Profiles))}}
+	for _, name := range sortedKeys(config.Profiles) {
+		var unknown []string
+		for _, flag := range sortedKeys(config.Profiles[name]) {
+			if !knownFlag(flag) {
+				unknown = append(unknown, flag)
+			}
+		}
+		if len(unknown) > 0 {
+			results = append(results, diagnosis{"profile " + name, false, "unknown flag(s): " + strings.Join(unknown, ", ")})
+		} else if err := checkProfileValues(config.Profiles[name]); err != nil {
+			results = append(results, diagnosis{"profile " + name, false, err.Error()})
+		} else {
+			results = append(results, diagnosis{"profile " + name, true, fmt.Sprintf("%d flag(s)", len(config.Profiles[name]))})
+		}
+	}
+	return results
+}
+
+func diagnoseCache() diagnosis {
+	dir, err := cacheDir()
+	if err != nil {
+		return diagnosis{"cache directory", false, err.Error()}
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return diagnosis{"cache directory", false, err.Error()}
+	}
+	file, err := os.CreateTemp(dir, "doctor-*")
+	if err != nil {
+		return diagnosis{"cache directory", false, fmt.Sprintf("%s isn't writable: %s", dir, err)}
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return diagnosis{"cache directory", true, dir + " is writable"}
+}
+
+// diagnoseData checks that product data can be fetched, skipping the cache so
+// it really reaches the API (or the --data-git-dir clone).
+func diagnoseData(ctx context.Context) diagnosis {
+	var body []byte
+	var source string
+	var err error
+	if dataGitDir != "" {
+		source = dataGitDir
+		body, _, err = fetchProductFromGit(ctx, "all")
+	} else {
+		source = strings.TrimSuffix(apiBaseURL, "/") + "/all.json"
+		body, _, err = fetchURL(ctx, source)
+	}
+	if err != nil {
+		return diagnosis{"product data", false, fmt.Sprintf("%s: %s", source, err)}
+	}
+	var products []string
+	if err := json.Unmarshal(body, &products); err != nil {
+		return diagnosis{"product data", false, fmt.Sprintf("%s returned an unexpected response: %s", source, err)}
+	}
+	return diagnosis{"product data", true, fmt.Sprintf("%s lists %d products", source, len(products))}
+}
+
+func printDiagnoses(w io.Writer, results []diagnosis, color bool) {
+	for _, d := range results {
+		mark := "✓"
+		if !d.ok {
+			mark = "✗"
+		}
+		if color {
+			code := "32"
+			if !d.ok {
+				code = "31"
+			}
+			mark = "\033[" + code + "m" + mark + "\033[0m"
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", mark, d.name, d.detail)
+	}
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config file, cache and API connection for problems",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results := diagnoseConfig(cmd)
+		results = append(results, diagnoseCache(), diagnoseData(cmd.Context()))
+		printDiagnoses(os.Stdout, results, isTerminal(os.Stdout))
+
+		failed := 0
+		for _, d := range results {
+			if !d.ok {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
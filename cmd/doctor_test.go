@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDoctorProfiles(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	config := writeFile(t, "config.yaml", `profiles:
+  ci: {accept: "supported,soon", min-remaining: 90d, tz: Europe/Prague}
+  eol: {accept: eol}
+  badaccept: {accept: bogus}
+  badremaining: {min-remaining: soon}
+  badtz: {tz: Nowhere/Special}
+  badint: {soon-days: many}
+  unknown: {colour: true}
+`)
+	got := run(t, nil, "--config", config, "--api-url", api.apiURL(), "doctor")
+	if got.code != 1 {
+		t.Errorf("exit code = %d, want 1\nstderr: %s", got.code, got.stderr)
+	}
+
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"ci", "✓ profile ci: 3 flag(s)"},
+		{"eol", "✓ profile eol: 1 flag(s)"},
+		{"badaccept", `✗ profile badaccept: Invalid --accept state "bogus"`},
+		{"badremaining", `✗ profile badremaining: Invalid --min-remaining "soon"`},
+		{"badtz", "✗ profile badtz: "},
+		{"badint", "✗ profile badint: invalid value many for --soon-days"},
+		{"unknown", "✗ profile unknown: unknown flag(s): colour"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			for _, line := range strings.Split(got.stdout, "\n") {
+				if strings.Contains(line, "profile "+tt.profile+":") {
+					if !strings.HasPrefix(line, tt.want) {
+						t.Errorf("got %q, want it to start with %q", line, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("no diagnosis for profile %s in:\n%s", tt.profile, got.stdout)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Drift is how the endoflife.date catalog differs from an expected list of
+// products.
+type Drift struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// readProductList reads one product per line, ignoring blank lines and #
+// comments.
+func readProductList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading product list: %s", err)
+	}
+	var products []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			products = append(products, line)
+		}
+	}
+	return products, scanner.Err()
+}
+
+// productDrift compares the current products against the expected ones.
+func productDrift(expected []string, current []string) Drift {
+	inExpected := map[string]bool{}
+	for _, product := range expected {
+		inExpected[product] = true
+	}
+	inCurrent := map[string]bool{}
+	for _, product := range current {
+		inCurrent[product] = true
+	}
+
+	drift := Drift{Added: []string{}, Removed: []string{}}
+	for product := range inCurrent {
+		if !inExpected[product] {
+			drift.Added = append(drift.Added, product)
+		}
+	}
+	for product := range inExpected {
+		if !inCurrent[product] {
+			drift.Removed = append(drift.Removed, product)
+		}
+	}
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	return drift
+}
+
+// checkProductsDriftCmd represents the check-products-drift command
+var checkProductsDriftCmd = &cobra.Command{
+	Use:   "check-products-drift <path-to-expected.txt>",
+	Short: "Compare the products endoflife.date tracks against an expected list",
+	Long: `Compare the products endoflife.date tracks against an expected list, one
+product per line, and report the products added to or removed from the
+catalog since. Fails if there are any.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expected, err := readProductList(args[0])
+		if err != nil {
+			return err
+		}
+		current, err := fetchProducts(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		drift := productDrift(expected, current)
+		if jsonOutput {
+			if err := printJSON(drift); err != nil {
+				return err
+			}
+		} else {
+			for _, product := range drift.Added {
+				fmt.Printf("+ %s\n", product)
+			}
+			for _, product := range drift.Removed {
+				fmt.Printf("- %s\n", product)
+			}
+		}
+
+		if len(drift.Added) > 0 || len(drift.Removed) > 0 {
+			return fmt.Errorf("%d product(s) added and %d removed since the expected list", len(drift.Added), len(drift.Removed))
+		}
+		if !jsonOutput {
+			fmt.Println("The products match the expected list")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkProductsDriftCmd)
+
+	checkProductsDriftCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the added and removed products as JSON")
+}
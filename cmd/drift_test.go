@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCheckProductsDrift(t *testing.T) {
+	products := testProducts()
+	products["all"] = `["nodejs","python","go","bun"]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name     string
+		expected string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+	}{
+		{"no drift", "# runtimes\nnodejs\npython\n\ngo  # toolchain\nbun\n", nil, 0, "The products match the expected list\n", ""},
+		{"added and removed", "nodejs\npython\nruby\ncentos\n", nil, 1, "+ bun\n+ go\n- centos\n- ruby\n", "Error: 2 product(s) added and 2 removed since the expected list\n"},
+		{"only added", "nodejs\npython\ngo\n", nil, 1, "+ bun\n", "Error: 1 product(s) added and 0 removed since the expected list\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := writeFile(t, "expected.txt", tt.expected)
+			got := run(t, nil, "--api-url", api.apiURL(), "check-products-drift", expected)
+			if got.code != tt.code || got.stdout != tt.stdout {
+				t.Errorf("exit code %d, stdout %q, want exit code %d and stdout %q\nstderr: %s", got.code, got.stdout, tt.code, tt.stdout, got.stderr)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		expected := writeFile(t, "expected.txt", "nodejs\npython\nruby\n")
+		got := run(t, nil, "--api-url", api.apiURL(), "check-products-drift", "--json", expected)
+		var drift Drift
+		if err := json.Unmarshal([]byte(got.stdout), &drift); err != nil {
+			t.Fatalf("parsing %q: %s", got.stdout, err)
+		}
+		want := Drift{Added: []string{"bun", "go"}, Removed: []string{"ruby"}}
+		if got.code != 1 || !reflect.DeepEqual(drift, want) {
+			t.Errorf("exit code %d, drift %+v, want exit code 1 and %+v", got.code, drift, want)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedSnapshotDateOnEveryResult(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: go\n  version: \"0.1\"\n")
+	snapshot, err := embeddedData.ReadFile("embedded/snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := "embedded data from " + strings.TrimSpace(string(snapshot))
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"check", []string{"check", "nodejs", "22"}, 1},
+		{"check json", []string{"check", "--json", "nodejs", "22"}, 0},
+		{"text", []string{"check-inventory", inventory}, 2},
+		{"table", []string{"check-inventory", "--format", "table", inventory}, 2},
+		{"grouped", []string{"check-inventory", "--format", "grouped", inventory}, 2},
+		{"json", []string{"check-inventory", "--json", inventory}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", "http://127.0.0.1:1/", "--retries", "0"}, tt.args...)...)
+			if n := strings.Count(got.stdout, note); n != tt.want {
+				t.Errorf("%q appears %d times, want %d\nstdout: %s", note, n, tt.want, got.stdout)
+			}
+			if tt.want == 0 && strings.Count(got.stdout, `"snapshot"`) == 0 {
+				t.Errorf("no snapshot field in JSON output\nstdout: %s", got.stdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+// statusSeverity orders the statuses from best to worst, for the overall
+// DATE_REAPER_STATUS of a run.
+var statusSeverity = map[Status]int{
+	endoflife.StatusSupported:   0,
+	endoflife.StatusUnknown:     1,
+	endoflife.StatusMaintenance: 2,
+	endoflife.StatusEOL:         3,
+}
+
+// shellQuote quotes a value for POSIX shells, which take everything between
+// single quotes literally except a single quote itself.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// writeEnv renders a summary of the run as shell variable assignments, to be
+// eval'd or sourced by scripts.
+func writeEnv(w io.Writer, report Report) error {
+	counts := map[Status]int{}
+	errorCount := 0
+	status := Status("")
+	var eolVersions []string
+	for _, r := range report.Results {
+		if r.Error != "" {
+			errorCount++
+			continue
+		}
+		counts[r.Status]++
+		if status == "" || statusSeverity[r.Status] > statusSeverity[status] {
+			status = r.Status
+		}
+		if r.IsEOL {
+			eolVersions = append(eolVersions, r.Product+" "+r.Version)
+		}
+	}
+
+	vars := []struct {
+		name  string
+		value string
+	}{
+		{"DATE_REAPER_OK", fmt.Sprint(report.OK)},
+		{"DATE_REAPER_EXIT_CODE", fmt.Sprint(report.ExitCode)},
+		{"DATE_REAPER_INCOMPLETE", fmt.Sprint(report.Incomplete)},
+		{"DATE_REAPER_STATUS", string(status)},
+		{"DATE_REAPER_CHECKED_COUNT", fmt.Sprint(len(report.Results))},
+		{"DATE_REAPER_SUPPORTED_COUNT", fmt.Sprint(counts[endoflife.StatusSupported])},
+		{"DATE_REAPER_MAINTENANCE_COUNT", fmt.Sprint(counts[endoflife.StatusMaintenance])},
+		{"DATE_REAPER_UNKNOWN_COUNT", fmt.Sprint(counts[endoflife.StatusUnknown])},
+		{"DATE_REAPER_EOL_COUNT", fmt.Sprint(counts[endoflife.StatusEOL])},
+		{"DATE_REAPER_ERROR_COUNT", fmt.Sprint(errorCount)},
+		{"DATE_REAPER_EOL_VERSIONS", strings.Join(eolVersions, ",")},
+	}
+	for _, v := range vars {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.name, shellQuote(v.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
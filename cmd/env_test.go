@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	for _, value := range []string{"", "plain", "two words", "it's", `$HOME "quoted" \n`, "'", "a\nb"} {
+		out, err := exec.Command("sh", "-c", `eval "v=$1"; printf %s "$v"`, "sh", shellQuote(value)).Output()
+		if err != nil {
+			t.Fatalf("sh rejected %s: %s", shellQuote(value), err)
+		}
+		if string(out) != value {
+			t.Errorf("%q came back from the shell as %q", value, out)
+		}
+	}
+}
+
+func TestEnvFormat(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", `- product: nodejs
+  version: "18"
+- product: python
+  version: "3.12"
+- product: python
+  version: "3.9"
+- product: nodejs
+  version: "16"
+`)
+
+	got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", "--format", "env", inventory)
+	want := strings.Join([]string{
+		"DATE_REAPER_OK='false'",
+		"DATE_REAPER_EXIT_CODE='1'",
+		"DATE_REAPER_INCOMPLETE='false'",
+		"DATE_REAPER_STATUS='eol'",
+		"DATE_REAPER_CHECKED_COUNT='4'",
+		"DATE_REAPER_SUPPORTED_COUNT='0'",
+		"DATE_REAPER_MAINTENANCE_COUNT='1'",
+		"DATE_REAPER_UNKNOWN_COUNT='0'",
+		"DATE_REAPER_EOL_COUNT='2'",
+		"DATE_REAPER_ERROR_COUNT='1'",
+		"DATE_REAPER_EOL_VERSIONS='nodejs 18,python 3.9'",
+	}, "\n") + "\n"
+	if got.code != 1 || got.stdout != want {
+		t.Fatalf("exit code %d, stdout =\n%s\nwant\n%s\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+
+	// Sourcing the output sets the variables.
+	script := writeFile(t, "report.env", got.stdout)
+	out, err := exec.Command("sh", "-c", `. "$1" && printf '%s|%s|%s' "$DATE_REAPER_STATUS" "$DATE_REAPER_EOL_COUNT" "$DATE_REAPER_EOL_VERSIONS"`, "sh", script).Output()
+	if err != nil {
+		t.Fatalf("sourcing the output: %s", err)
+	}
+	if want := "eol|2|nodejs 18,python 3.9"; string(out) != want {
+		t.Errorf("sourced variables = %q, want %q", out, want)
+	}
+}
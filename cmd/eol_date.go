@@ -0,0 +1,38 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+// eolDateCmd represents the eol-date command
+var eolDateCmd = &cobra.Command{
+	Use:   "eol-date <name> <version>",
+	Short: "Print only the EOL date of a software version",
+	Long: `Print only the EOL date of a software version, as YYYY-MM-DD, for use in
+scripts. Prints nothing and fails if the version has no EOL date.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, version := args[0], args[1]
+		v, _, err := CheckVersion(cmd.Context(), name, version)
+		if err != nil {
+			return err
+		}
+		date := endoflife.EOLDate(v)
+		if date == "" {
+			return fmt.Errorf("%s %s has no EOL date", capitalize(name), version)
+		}
+		fmt.Println(date)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eolDateCmd)
+}
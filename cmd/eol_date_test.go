@@ -0,0 +1,35 @@
+package cmd
+
+import "testing"
+
+func TestEOLDate(t *testing.T) {
+	products := testProducts()
+	products["deno"] = `[{"cycle":"2","releaseDate":"2024-10-09","eol":false,"latest":"2.1.4"}]`
+	products["hugo"] = `[{"cycle":"0.120","releaseDate":"2023-10-30","latest":"0.120.4"}]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"known date", []string{"nodejs", "18"}, 0, "2025-04-30\n", ""},
+		{"matched cycle", []string{"python", "3.9.7"}, 0, "2025-10-31\n", ""},
+		{"eol false", []string{"deno", "2"}, 1, "", "Error: Deno 2 has no EOL date\n"},
+		{"no eol field", []string{"hugo", "0.120"}, 1, "", "Error: Hugo 0.120 has no EOL date\n"},
+		{"unknown version", []string{"nodejs", "8"}, 1, "", "Error: Version not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "eol-date"}, tt.args...)...)
+			if got.code != tt.code || got.stdout != tt.stdout {
+				t.Errorf("exit code %d, stdout %q, want exit code %d and stdout %q\nstderr: %s", got.code, got.stdout, tt.code, tt.stdout, got.stderr)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainExitCode(t *testing.T) {
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: python\n  version: \"3.12\"\n- product: nodejs\n  version: \"18\"\n")
+
+	tests := []struct {
+		name   string
+		slow   bool
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"passed", false, []string{"check", "nodejs", "22"}, 0, "exit 0 (passed)\n"},
+		{"failed", false, []string{"check", "nodejs", "18"}, 1, "Error: EOL\nexit 1 (failed): EOL\n"},
+		{"bulk failed", false, []string{"check-inventory", inventory}, 1, "Error: 1 version(s) are EOL\nexit 1 (failed): 1 version(s) are EOL\n"},
+		{"warning", false, []string{"--warn-exit-code", "78", "check", "nodejs", "22", "--soon-days", "120"}, 78, "Error: 1 version(s) reach EOL within 120 days\nexit 78 (warning): 1 version(s) reach EOL within 120 days\n"},
+		{"incomplete", true, []string{"--deadline", "300ms", "check-inventory", "-c", "1", inventory}, 3, "Error: Incomplete results, deadline exceeded after checking 1 of 3 items\nexit 3 (incomplete): Incomplete results, deadline exceeded after checking 1 of 3 items\n"},
+		{"network used", false, []string{"--no-network-on-cache-hit", "check", "nodejs", "22"}, 4, "Error: 1 network request(s) were made, but --no-network-on-cache-hit expects the cache to cover everything\nexit 4 (network used): 1 network request(s) were made, but --no-network-on-cache-hit expects the cache to cover everything\n"},
+		{"usage mistake", false, []string{"check", "nodejs"}, 1, "Error: accepts 2 arg(s), received 1\nexit 1 (failed): accepts 2 arg(s), received 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			if tt.slow {
+				api.slow("python", 0, 10*time.Second)
+			}
+			env := []string{"DATE_REAPER_CACHE_DIR=" + t.TempDir()}
+			got := run(t, env, append([]string{"--api-url", api.apiURL(), "--explain-exit-code"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+var apiBaseURL = "https://endoflife.date/api/"
+
+// unixSocket, when set, is where API requests are sent instead of the host
+// of --api-url, for mirrors running as a sidecar. The URL still gives the
+// path of each request.
+var unixSocket string
+
+var apiClientsMu sync.Mutex
+
+// apiClients holds the HTTP client for each --unix-socket (the empty one for
+// plain TCP), shared by all requests so concurrent checks reuse connections.
+var apiClients = map[string]*http.Client{}
+
+// apiClient returns the HTTP client API requests are made with. It is safe to
+// call from several goroutines.
+func apiClient() *http.Client {
+	apiClientsMu.Lock()
+	defer apiClientsMu.Unlock()
+	if client, ok := apiClients[unixSocket]; ok {
+		return client
+	}
+
+	client := &http.Client{}
+	if unixSocket != "" {
+		socket := unixSocket
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+	}
+	apiClients[unixSocket] = client
+	return client
+}
+
+type Provenance = endoflife.Provenance
+
+// cliClient is the Client the commands look products up with, going through
+// the cache, the embedded snapshot and --data-git-dir as configured by the
+// flags. It keeps nothing in memory, so the cache flags alone decide what is
+// reused.
+var cliClient = endoflife.NewClient("", endoflife.Options{Fetch: loadProduct, TTL: -1})
+
+// fetchProduct returns the raw API response for a product. It is safe to call
+// from several goroutines, see endoflife.Client. Callers must not modify the
+// returned body, since it is shared.
+func fetchProduct(ctx context.Context, name string) ([]byte, Provenance, error) {
+	return cliClient.Product(ctx, name)
+}
+
+// loadProduct does the lookup behind fetchProduct, going through the cache as
+// described next to the cache flags and falling back to the embedded
+// snapshot, or reads the data from git with --data-git-dir.
+func loadProduct(ctx context.Context, name string) ([]byte, Provenance, error) {
+	if dataGitDir != "" {
+		return fetchProductFromGit(ctx, name)
+	}
+
+	url := strings.TrimSuffix(apiBaseURL, "/") + "/" + name + ".json"
+
+	var cached []byte
+	var cachedAt time.Time
+	if cacheReadable() {
+		body, storedAt, err := readCache(name)
+		if err == nil {
+			if time.Since(storedAt) < cacheTTL && !refreshCache {
+				logf("%s: using cached response from %s", url, storedAt.Format(time.RFC3339))
+				return body, Provenance{URL: url, Status: http.StatusOK, FetchedAt: storedAt, FromCache: true}, nil
+			}
+			cached, cachedAt = body, storedAt
+		}
+	}
+
+	fetchedAt := time.Now()
+	body, status, err := fetchURL(ctx, url)
+	if err != nil {
+		if cached != nil {
+			logf("%s: %s, falling back to cached response from %s", url, err, cachedAt.Format(time.RFC3339))
+			return cached, Provenance{URL: url, Status: http.StatusOK, FetchedAt: cachedAt, FromCache: true}, nil
+		}
+		// A product the API doesn't know isn't worth a fallback, the
+		// snapshot can only be older.
+		if status != http.StatusNotFound && ctx.Err() == nil {
+			if body, provenance, ok := embeddedProduct(name); ok {
+				logf("%s: %s, falling back to embedded data", url, err)
+				return body, provenance, nil
+			}
+		}
+		return nil, Provenance{URL: url, Status: status, FetchedAt: fetchedAt}, err
+	}
+
+	if cacheWritable() {
+		// A failed cache write shouldn't fail the check itself.
+		_ = writeCache(name, body)
+	}
+	return body, Provenance{URL: url, Status: status, FetchedAt: fetchedAt}, nil
+}
+
+// fetchURL fetches url, retrying transient failures up to --retries times
+// while the run's retry budget lasts.
+func fetchURL(ctx context.Context, url string) ([]byte, int, error) {
+	for attempt := 0; ; attempt++ {
+		body, status, err := fetchOnce(ctx, url)
+		if err == nil || ctx.Err() != nil || !isTransient(status) || attempt >= retries || !takeRetry() {
+			return body, status, err
+		}
+		select {
+		case <-time.After(retryDelay(attempt)):
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	}
+}
+
+func fetchOnce(ctx context.Context, url string) ([]byte, int, error) {
+	httpClient := apiClient()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Set("User-Agent", "date-reaper-cli")
+	headers, err := extraHeaders()
+	if err != nil {
+		return nil, 0, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	logf("GET %s", url)
+	for name := range headers {
+		logf("  %s: %s", name, redacted)
+	}
+	traceRequest(req, headers)
+	networkRequests.Add(1)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	logf("%s: %d, %d bytes", url, resp.StatusCode, len(body))
+	traceResponse(resp, body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Error: Server returned status %d", resp.StatusCode)
+	}
+	return body, resp.StatusCode, err
+}
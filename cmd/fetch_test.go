@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProvenance(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	env := []string{"DATE_REAPER_CACHE_DIR=" + t.TempDir()}
+	start := time.Now().Add(-time.Second)
+
+	var fetchedAt time.Time
+	tests := []struct {
+		name      string
+		fromCache bool
+	}{
+		{"fetched", false},
+		{"cached", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, env, "--api-url", api.apiURL(), "check", "--json", "--include-provenance", "nodejs", "22")
+			var result Result
+			if err := json.Unmarshal([]byte(got.stdout), &result); err != nil {
+				t.Fatalf("parsing result: %s\nstderr: %s", err, got.stderr)
+			}
+			p := result.Provenance
+			if p == nil {
+				t.Fatalf("no provenance in %s", got.stdout)
+			}
+			if want := api.URL + "/nodejs.json"; p.URL != want {
+				t.Errorf("url = %q, want %q", p.URL, want)
+			}
+			if p.Status != 200 {
+				t.Errorf("status = %d, want 200", p.Status)
+			}
+			if p.FromCache != tt.fromCache {
+				t.Errorf("fromCache = %t, want %t", p.FromCache, tt.fromCache)
+			}
+			if p.FetchedAt.Before(start) || p.FetchedAt.After(time.Now()) {
+				t.Errorf("fetchedAt = %s, want the time of the first run", p.FetchedAt)
+			}
+			// A cached response dates from when it was stored, right after
+			// the first run fetched it.
+			if tt.fromCache && p.FetchedAt.Sub(fetchedAt).Abs() > time.Second {
+				t.Errorf("fetchedAt = %s, want about the time it was fetched, %s", p.FetchedAt, fetchedAt)
+			}
+			fetchedAt = p.FetchedAt
+		})
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(testProducts()["nodejs"]))
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		socket string
+		code   int
+		paths  []string
+		stderr string
+	}{
+		{"served over the socket", socket, 0, []string{"/mirror/nodejs.json"}, ""},
+		{"missing socket", filepath.Join(t.TempDir(), "missing.sock"), 1, nil, "missing.sock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			paths = nil
+			mu.Unlock()
+			got := run(t, nil, "--unix-socket", tt.socket, "--api-url", "http://mirror.invalid/mirror/", "--retries", "0", "--no-embedded", "check", "nodejs", "22")
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(paths, " ") != strings.Join(tt.paths, " ") {
+				t.Errorf("server got %q, want %q", paths, tt.paths)
+			}
+		})
+	}
+}
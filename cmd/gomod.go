@@ -0,0 +1,97 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// goModuleProducts maps Go modules to the endoflife.date products they are
+// released with.
+var goModuleProducts = map[string]string{
+	"github.com/containerd/containerd": "containerd",
+	"github.com/docker/docker":         "docker-engine",
+	"go.etcd.io/etcd/client/v3":        "etcd",
+	"k8s.io/api":                       "kubernetes",
+	"k8s.io/apimachinery":              "kubernetes",
+	"k8s.io/client-go":                 "kubernetes",
+}
+
+// goModuleVersion turns a module version into the product version it stands
+// for. The k8s.io libraries are tagged v0.N for Kubernetes 1.N.
+func goModuleVersion(path string, version string) string {
+	version = strings.TrimPrefix(semver.Canonical(strings.TrimSuffix(version, "+incompatible")), "v")
+	if strings.HasPrefix(path, "k8s.io/") {
+		if rest, ok := strings.CutPrefix(version, "0."); ok {
+			return "1." + rest
+		}
+	}
+	return version
+}
+
+// goModRefs extracts the Go version of a go.mod, from its go and toolchain
+// directives, and the required modules endoflife.date tracks.
+func goModRefs(path string, data []byte) ([]checkRef, error) {
+	file, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []checkRef
+	if file.Go != nil {
+		refs = append(refs, checkRef{Source: "go", Label: "go " + file.Go.Version, Product: "go", Version: file.Go.Version})
+	}
+	if file.Toolchain != nil {
+		version := strings.TrimPrefix(file.Toolchain.Name, "go")
+		refs = append(refs, checkRef{Source: "toolchain", Label: file.Toolchain.Name, Product: "go", Version: version})
+	}
+	for _, require := range file.Require {
+		product, ok := goModuleProducts[require.Mod.Path]
+		if !ok {
+			continue
+		}
+		refs = append(refs, checkRef{
+			Source:  require.Mod.Path,
+			Label:   require.Mod.String(),
+			Product: product,
+			Version: goModuleVersion(require.Mod.Path, require.Mod.Version),
+		})
+	}
+	return refs, nil
+}
+
+// checkGoDepsCmd represents the check-go-deps command
+var checkGoDepsCmd = &cobra.Command{
+	Use:         "check-go-deps <path-to-go.mod>",
+	Annotations: map[string]string{inputsAnnotation: "go.mod"},
+	Short:       "Check the Go version and tracked dependencies of a go.mod for EOL",
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("Error reading go.mod: %s", err)
+		}
+		refs, err := goModRefs(args[0], data)
+		if err != nil {
+			return fmt.Errorf("Error parsing go.mod: %s", err)
+		}
+		return checkRefs(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkGoDepsCmd)
+
+	addMinRemainingFlag(checkGoDepsCmd)
+	addRemediationFlag(checkGoDepsCmd)
+	addJSONFlags(checkGoDepsCmd, "the report of the Go version and dependencies")
+	addPlanFlag(checkGoDepsCmd)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goModFixture = `module example.com/service
+
+go 1.21.3
+
+toolchain go1.22.4
+
+require (
+	github.com/docker/docker v24.0.7+incompatible
+	github.com/spf13/cobra v1.8.0
+	k8s.io/client-go v0.29.3
+)
+
+require k8s.io/apimachinery v0.29.3 // indirect
+`
+
+func TestGoModuleVersion(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		want    string
+	}{
+		{"k8s.io/client-go", "v0.29.3", "1.29.3"},
+		{"k8s.io/api", "v0.30.0-rc.1", "1.30.0-rc.1"},
+		{"github.com/docker/docker", "v24.0.7+incompatible", "24.0.7"},
+		{"go.etcd.io/etcd/client/v3", "v3.5.12", "3.5.12"},
+		{"github.com/containerd/containerd", "v1.7", "1.7.0"},
+	}
+	for _, tt := range tests {
+		if got := goModuleVersion(tt.path, tt.version); got != tt.want {
+			t.Errorf("goModuleVersion(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestGoModRefs(t *testing.T) {
+	got, err := goModRefs("go.mod", []byte(goModFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []checkRef{
+		{Source: "go", Label: "go 1.21.3", Product: "go", Version: "1.21.3"},
+		{Source: "toolchain", Label: "go1.22.4", Product: "go", Version: "1.22.4"},
+		{Source: "github.com/docker/docker", Label: "github.com/docker/docker@v24.0.7+incompatible", Product: "docker-engine", Version: "24.0.7"},
+		{Source: "k8s.io/client-go", Label: "k8s.io/client-go@v0.29.3", Product: "kubernetes", Version: "1.29.3"},
+		{Source: "k8s.io/apimachinery", Label: "k8s.io/apimachinery@v0.29.3", Product: "kubernetes", Version: "1.29.3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if _, err := goModRefs("go.mod", []byte("module example.com/broken\nrequire (\n")); err == nil {
+		t.Error("expected an error for a malformed go.mod")
+	}
+}
+
+func TestCheckGoDeps(t *testing.T) {
+	products := testProducts()
+	products["go"] = `[
+{"cycle":"1.22","releaseDate":"2024-02-06","eol":"` + daysFromNow(120) + `","latest":"1.22.4"},
+{"cycle":"1.21","releaseDate":"2023-08-08","eol":"2024-08-13","latest":"1.21.13"}
+]`
+	api := newAPIServer(t, products)
+	goMod := writeFile(t, "go.mod", "module example.com/service\n\ngo 1.21.3\n\ntoolchain go1.22.4\n\nrequire github.com/spf13/cobra v1.8.0\n")
+
+	got := run(t, nil, "--api-url", api.apiURL(), "check-go-deps", goMod)
+	want := strings.Join([]string{
+		"go: Go 1.21.3 is EOL since 2024-08-13",
+		"toolchain: Go 1.22.4 is not EOL yet. It will be EOL on " + daysFromNow(120),
+	}, "\n") + "\n"
+	if got.code != 1 || got.stdout != want {
+		t.Errorf("exit code %d, stdout %q, want exit code 1 and stdout %q\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+}
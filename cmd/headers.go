@@ -0,0 +1,51 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var apiHeaders []string
+var apiToken string
+
+// redacted replaces header values we send in anything we print.
+const redacted = "[redacted]"
+
+// extraHeaders returns the headers set with --api-header and --api-token (or
+// $DATE_REAPER_API_TOKEN).
+func extraHeaders() (http.Header, error) {
+	headers := http.Header{}
+	for _, header := range apiHeaders {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("Invalid --api-header %q, expected 'Name: value'", header)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+
+	token := apiToken
+	if token == "" {
+		token = os.Getenv("DATE_REAPER_API_TOKEN")
+	}
+	if token != "" {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+	return headers, nil
+}
+
+// redactHeaders returns a copy of headers with the values of every header we
+// added ourselves replaced, so they never end up in logs or traces.
+func redactHeaders(headers http.Header, added http.Header) http.Header {
+	clean := headers.Clone()
+	for name := range added {
+		clean.Set(name, redacted)
+	}
+	return clean
+}
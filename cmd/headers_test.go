@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAPIHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		env    []string
+		header string
+		want   string
+	}{
+		{"api-header", []string{"--api-header", "X-Mirror-Key: s3cret"}, nil, "X-Mirror-Key", "s3cret"},
+		{"api-token", []string{"--api-token", "s3cret"}, nil, "Authorization", "Bearer s3cret"},
+		{"token from the environment", nil, []string{"DATE_REAPER_API_TOKEN=s3cret"}, "Authorization", "Bearer s3cret"},
+		{"api-token wins over the environment", []string{"--api-token", "s3cret"}, []string{"DATE_REAPER_API_TOKEN=other"}, "Authorization", "Bearer s3cret"},
+	}
+	for _, tt := range tests {
+		for _, logging := range []string{"-v", "--trace"} {
+			t.Run(tt.name+" "+logging, func(t *testing.T) {
+				var mu sync.Mutex
+				var got []string
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					got = append(got, r.Header.Get(tt.header))
+					mu.Unlock()
+					w.Write([]byte(testProducts()["nodejs"]))
+				}))
+				defer server.Close()
+
+				args := append([]string{"--api-url", server.URL + "/", logging, "check", "nodejs", "22"}, tt.args...)
+				res := run(t, tt.env, args...)
+				if res.code != 0 {
+					t.Fatalf("exit code = %d\nstderr: %s", res.code, res.stderr)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if len(got) != 1 || got[0] != tt.want {
+					t.Errorf("server got %s %q, want %q", tt.header, got, tt.want)
+				}
+				if strings.Contains(res.stderr, "s3cret") {
+					t.Errorf("%s logged the header value:\n%s", logging, res.stderr)
+				}
+				if !strings.Contains(res.stderr, tt.header+": "+redacted) {
+					t.Errorf("%s didn't log the header as redacted:\n%s", logging, res.stderr)
+				}
+			})
+		}
+	}
+}
+
+func TestInvalidAPIHeader(t *testing.T) {
+	got := run(t, nil, "--api-url", "http://127.0.0.1:1/", "--api-header", "no colon", "check", "nodejs", "22")
+	if got.code != 1 || !strings.Contains(got.stderr, `Invalid --api-header "no colon"`) {
+		t.Errorf("exit code = %d, stderr = %q, want the header rejected", got.code, got.stderr)
+	}
+}
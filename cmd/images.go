@@ -0,0 +1,105 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// imageProducts maps official Docker image names to endoflife.date products.
+var imageProducts = map[string]string{
+	"alpine":          "alpine",
+	"amazonlinux":     "amazon-linux",
+	"centos":          "centos",
+	"debian":          "debian",
+	"eclipse-temurin": "eclipse-temurin",
+	"elasticsearch":   "elasticsearch",
+	"elixir":          "elixir",
+	"erlang":          "erlang",
+	"fedora":          "fedora",
+	"golang":          "go",
+	"haproxy":         "haproxy",
+	"httpd":           "apache-http-server",
+	"mariadb":         "mariadb",
+	"mongo":           "mongodb",
+	"mysql":           "mysql",
+	"nginx":           "nginx",
+	"node":            "nodejs",
+	"perl":            "perl",
+	"php":             "php",
+	"postgres":        "postgresql",
+	"python":          "python",
+	"rabbitmq":        "rabbitmq",
+	"redis":           "redis",
+	"ruby":            "ruby",
+	"traefik":         "traefik",
+	"ubuntu":          "ubuntu",
+}
+
+var tagVersionPattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)`)
+
+// parseImage splits an image reference like "docker.io/library/node:18-alpine"
+// into its repository name ("node") and tag ("18-alpine"). Digests are
+// dropped and a missing tag is reported as "latest".
+func parseImage(ref string) (string, string) {
+	ref, _, _ = strings.Cut(ref, "@")
+
+	name, tag := ref, "latest"
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, tag = ref[:i], ref[i+1:]
+	}
+
+	// A first path component with a dot or port in it is a registry host.
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		name = rest
+	}
+	name = strings.TrimPrefix(name, "library/")
+	return name, tag
+}
+
+// imageVersion maps an image reference to the product and version to check,
+// e.g. "node:18-alpine" to nodejs 18.
+func imageVersion(ref string) (string, string, error) {
+	name, tag := parseImage(ref)
+	product, ok := imageProducts[name]
+	if !ok {
+		return "", "", fmt.Errorf("%s isn't a known image", name)
+	}
+	match := tagVersionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return "", "", fmt.Errorf("tag %q doesn't pin a version", tag)
+	}
+	return product, match[1], nil
+}
+
+// imageRef is an image reference found in a file, along with where in the
+// file it was found. Skip explains why an entry isn't checked, e.g. because
+// it is built from source.
+type imageRef struct {
+	Source string
+	Image  string
+	Skip   string
+}
+
+// checkImages checks the products behind a list of image references.
+func checkImages(ctx context.Context, images []imageRef) error {
+	refs := make([]checkRef, len(images))
+	for i, image := range images {
+		refs[i] = checkRef{Source: image.Source, Label: image.Image, Skip: image.Skip}
+		if image.Skip != "" {
+			continue
+		}
+		product, version, err := imageVersion(image.Image)
+		if err != nil {
+			refs[i].Skip = fmt.Sprintf("%s, %s", image.Image, err)
+			continue
+		}
+		refs[i].Product, refs[i].Version = product, version
+	}
+	return checkRefs(ctx, refs)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+// ProductInfo is a digest of all release cycles of a product.
+type ProductInfo struct {
+	Product       string `json:"product"`
+	Cycles        int    `json:"cycles"`
+	OldestRelease string `json:"oldestRelease,omitempty"`
+	NewestRelease string `json:"newestRelease,omitempty"`
+	Supported     int    `json:"supported"`
+	EOL           int    `json:"eol"`
+	Unknown       int    `json:"unknown,omitempty"`
+	NewestLTS     string `json:"newestLts,omitempty"`
+}
+
+// isLTS reports whether a cycle is a long-term support release by now. The
+// API gives either a boolean or the date the cycle became LTS.
+func isLTS(v SoftwareVersion, now string) bool {
+	switch lts := v.LTS.(type) {
+	case bool:
+		return lts
+	case string:
+		return lts <= now
+	}
+	return false
+}
+
+// productInfo digests a product's cycles. Cycles in maintenance mode count as
+// supported, since they still get security fixes.
+func productInfo(name string, versions []SoftwareVersion, now string) ProductInfo {
+	info := ProductInfo{Product: name, Cycles: len(versions)}
+	var newestLTSRelease string
+	for _, v := range versions {
+		if v.ReleaseDate != "" {
+			if info.OldestRelease == "" || v.ReleaseDate < info.OldestRelease {
+				info.OldestRelease = v.ReleaseDate
+			}
+			if v.ReleaseDate > info.NewestRelease {
+				info.NewestRelease = v.ReleaseDate
+			}
+		}
+		switch endoflife.CycleStatus(v, now) {
+		case endoflife.StatusEOL:
+			info.EOL++
+		case endoflife.StatusUnknown:
+			info.Unknown++
+		default:
+			info.Supported++
+		}
+		if isLTS(v, now) && (info.NewestLTS == "" || v.ReleaseDate > newestLTSRelease) {
+			info.NewestLTS, newestLTSRelease = v.Cycle, v.ReleaseDate
+		}
+	}
+	return info
+}
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Summarize the release history of a product",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		versions, _, err := fetchCycles(cmd.Context(), name)
+		if err != nil {
+			return err
+		}
+
+		info := productInfo(name, versions, today())
+		if jsonOutput {
+			return printJSON(info)
+		}
+
+		fmt.Printf("%s has %d release cycles\n", capitalize(name), info.Cycles)
+		if info.OldestRelease != "" {
+			fmt.Printf("  Oldest release: %s\n", info.OldestRelease)
+			fmt.Printf("  Newest release: %s\n", info.NewestRelease)
+		}
+		fmt.Printf("  Supported:      %d\n", info.Supported)
+		fmt.Printf("  EOL:            %d\n", info.EOL)
+		if info.Unknown > 0 {
+			fmt.Printf("  Unknown:        %d\n", info.Unknown)
+		}
+		if info.NewestLTS != "" {
+			fmt.Printf("  Newest LTS:     %s\n", info.NewestLTS)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	infoCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the summary as JSON")
+}
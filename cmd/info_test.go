@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProductInfo(t *testing.T) {
+	versions := []SoftwareVersion{
+		{Cycle: "24", ReleaseDate: "2025-05-06", LTS: "2025-10-28", EOL: "2028-04-30"},
+		{Cycle: "23", ReleaseDate: "2024-10-16", LTS: false, EOL: "2025-06-01"},
+		{Cycle: "22", ReleaseDate: "2024-04-24", LTS: "2024-10-29", EOL: "2027-04-30"},
+		{Cycle: "20", ReleaseDate: "2023-04-18", LTS: "2023-10-24", EOL: "2026-04-30"},
+		{Cycle: "18", ReleaseDate: "2022-04-19", LTS: "2022-10-25", EOL: "2025-04-30"},
+		{Cycle: "next"},
+	}
+	tests := []struct {
+		now  string
+		want ProductInfo
+	}{
+		{"2025-07-01", ProductInfo{Product: "nodejs", Cycles: 6, OldestRelease: "2022-04-19", NewestRelease: "2025-05-06", Supported: 3, EOL: 2, Unknown: 1, NewestLTS: "22"}},
+		{"2025-11-01", ProductInfo{Product: "nodejs", Cycles: 6, OldestRelease: "2022-04-19", NewestRelease: "2025-05-06", Supported: 3, EOL: 2, Unknown: 1, NewestLTS: "24"}},
+		{"2026-05-01", ProductInfo{Product: "nodejs", Cycles: 6, OldestRelease: "2022-04-19", NewestRelease: "2025-05-06", Supported: 2, EOL: 3, Unknown: 1, NewestLTS: "24"}},
+	}
+	for _, tt := range tests {
+		if got := productInfo("nodejs", versions, tt.now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("productInfo on %s = %+v, want %+v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestInfo(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	got := run(t, nil, "--api-url", api.apiURL(), "info", "nodejs")
+	want := strings.Join([]string{
+		"Nodejs has 3 release cycles",
+		"  Oldest release: 2022-04-19",
+		"  Newest release: 2024-04-24",
+		"  Supported:      2",
+		"  EOL:            1",
+		"  Newest LTS:     22",
+	}, "\n") + "\n"
+	if got.code != 0 || got.stdout != want {
+		t.Errorf("exit code %d, stdout %q, want %q\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+
+	got = run(t, nil, "--api-url", api.apiURL(), "info", "python", "--json")
+	var info ProductInfo
+	if err := json.Unmarshal([]byte(got.stdout), &info); err != nil {
+		t.Fatalf("parsing %q: %s", got.stdout, err)
+	}
+	wantInfo := ProductInfo{Product: "python", Cycles: 2, OldestRelease: "2020-10-05", NewestRelease: "2023-10-02", Supported: 1, EOL: 1}
+	if !reflect.DeepEqual(info, wantInfo) {
+		t.Errorf("info = %+v, want %+v", info, wantInfo)
+	}
+}
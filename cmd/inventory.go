@@ -0,0 +1,183 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// InventoryItem is one product version listed in an inventory file. Source
+// lists the files it came from when several are checked at once.
+type InventoryItem struct {
+	Product string `yaml:"product"`
+	Version string `yaml:"version"`
+	Source  string `yaml:"-"`
+}
+
+// dedupeAcrossFiles merges the entries several inventory files share, so
+// each product version is checked and reported once.
+var dedupeAcrossFiles bool
+
+// dedupeItems merges items with the same product and version into the first
+// of them, joining the files they came from.
+func dedupeItems(items []InventoryItem) []InventoryItem {
+	type key struct{ product, version string }
+	var merged []InventoryItem
+	index := map[key]int{}
+	for _, item := range items {
+		k := key{item.Product, item.Version}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+		if !slices.Contains(strings.Split(merged[i].Source, ", "), item.Source) {
+			merged[i].Source += ", " + item.Source
+		}
+	}
+	return merged
+}
+
+// productKey and versionKey are where each inventory entry keeps its product
+// and version, as dot-separated paths like "image.tag", so manifests of any
+// shape can be read without a parser of their own.
+var productKey string
+var versionKey string
+
+// inventoryNode is a value in an inventory entry: a mapping or a scalar.
+// Scalars keep their text as written, so an unquoted 3.10 stays "3.10"
+// rather than becoming the number 3.1.
+type inventoryNode struct {
+	fields map[string]inventoryNode
+	scalar string
+}
+
+func (n *inventoryNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&n.fields); err == nil {
+		return nil
+	}
+	n.fields = nil
+	// Lists can't hold a product or version, so they are left empty.
+	_ = unmarshal(&n.scalar)
+	return nil
+}
+
+// lookupKeyPath follows a dot-separated path through nested mappings to a
+// scalar.
+func lookupKeyPath(node inventoryNode, path string) (string, bool) {
+	for _, key := range strings.Split(path, ".") {
+		var ok bool
+		if node, ok = node.fields[key]; !ok {
+			return "", false
+		}
+	}
+	return node.scalar, node.fields == nil
+}
+
+// readInventory reads an inventory file, a YAML (or JSON) list of entries
+// with the product and version under --product-key and --version-key.
+func readInventory(path string) ([]InventoryItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading inventory file: %s", err)
+	}
+	var entries []inventoryNode
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("Error parsing inventory file: %s", err)
+	}
+
+	items := make([]InventoryItem, 0, len(entries))
+	for i, entry := range entries {
+		product, ok := lookupKeyPath(entry, productKey)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing inventory file: entry %d has no %q", i+1, productKey)
+		}
+		version, ok := lookupKeyPath(entry, versionKey)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing inventory file: entry %d has no %q", i+1, versionKey)
+		}
+		items = append(items, InventoryItem{Product: product, Version: version})
+	}
+	return items, nil
+}
+
+// checkInventoryCmd represents the check-inventory command
+var checkInventoryCmd = &cobra.Command{
+	Use:         "check-inventory <path-to-inventory.yaml>...",
+	Annotations: map[string]string{inputsAnnotation: "inventory.yaml,inventory.json"},
+	Short:       "Check every product version listed in an inventory file",
+	Long: `Check every product version listed in an inventory file, a YAML or JSON
+list of entries like:
+
+  - product: nodejs
+    version: "18"
+  - product: python
+    version: "3.12"
+
+Manifests of other shapes can be read by pointing --product-key and
+--version-key at where each entry keeps them:
+
+  date-reaper check-inventory versions.yaml --product-key name --version-key image.tag
+
+Given several files, each result says which of them listed it, and product
+versions listed in more than one are checked and reported once.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var items []InventoryItem
+		for _, path := range args {
+			fileItems, err := readInventory(path)
+			if err != nil {
+				return err
+			}
+			if len(args) > 1 {
+				for i := range fileItems {
+					fileItems[i].Source = path
+				}
+			}
+			items = append(items, fileItems...)
+		}
+		if dedupeAcrossFiles && len(args) > 1 {
+			items = dedupeItems(items)
+		}
+		if plan {
+			showPlan(items)
+			return nil
+		}
+
+		results, incomplete := evaluateItems(cmd.Context(), items)
+		verdict := bulkVerdict(results, incomplete)
+		if err := writeReport(newReport(results, verdict)); err != nil {
+			return err
+		}
+		remediate(cmd.Context(), results, verdict)
+		return verdict
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkInventoryCmd)
+
+	checkInventoryCmd.Flags().StringVar(&productKey, "product-key", "product", "Key of each entry holding the product, dot-separated for nested keys (e.g. component.name)")
+	checkInventoryCmd.Flags().StringVar(&versionKey, "version-key", "version", "Key of each entry holding the version, dot-separated for nested keys (e.g. image.tag)")
+	checkInventoryCmd.Flags().BoolVar(&dedupeAcrossFiles, "dedupe-across-files", true, "Check and report product versions listed in several of the files once")
+	checkInventoryCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "Number of versions to check at the same time")
+	checkInventoryCmd.Flags().StringSliceVar(&acceptStates, "accept", nil, "Only pass if every version is in one of these states: supported, maintenance, unknown, eol, soon")
+	checkInventoryCmd.Flags().IntVar(&soonDays, "soon-days", 30, "Number of days before EOL a version is considered to be EOL soon")
+	checkInventoryCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text, grouped, table, csv, env, json or openmetrics")
+	checkInventoryCmd.Flags().BoolVar(&noHeader, "no-header", false, "Leave out the header row of table and csv output")
+	checkInventoryCmd.Flags().BoolVar(&mergeDuplicateProducts, "merge-duplicate-products", false, "Group text output under one header per product")
+	checkInventoryCmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "Write the output to a file instead of stdout")
+	addMinRemainingFlag(checkInventoryCmd)
+	addRemediationFlag(checkInventoryCmd)
+	addJSONFlags(checkInventoryCmd, "the report")
+	addPlanFlag(checkInventoryCmd)
+}
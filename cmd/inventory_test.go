@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupeItems(t *testing.T) {
+	items := []InventoryItem{
+		{Product: "nodejs", Version: "22", Source: "a.yaml"},
+		{Product: "python", Version: "3.12", Source: "a.yaml"},
+		{Product: "nodejs", Version: "22", Source: "b.yaml"},
+		{Product: "nodejs", Version: "22", Source: "b.yaml"},
+		{Product: "nodejs", Version: "20", Source: "b.yaml"},
+	}
+	want := []InventoryItem{
+		{Product: "nodejs", Version: "22", Source: "a.yaml, b.yaml"},
+		{Product: "python", Version: "3.12", Source: "a.yaml"},
+		{Product: "nodejs", Version: "20", Source: "b.yaml"},
+	}
+	if got := dedupeItems(items); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckInventoryAcrossFiles(t *testing.T) {
+	a := writeFile(t, "a.yaml", "- product: nodejs\n  version: \"22\"\n- product: python\n  version: \"3.12\"\n")
+	b := writeFile(t, "b.yaml", "- product: nodejs\n  version: \"22\"\n- product: nodejs\n  version: \"20\"\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		sources []string
+	}{
+		{"deduped", nil, []string{a + ", " + b, a, b}},
+		{"not deduped", []string{"--dedupe-across-files=false"}, []string{a, a, b, b}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			args := append([]string{"--api-url", api.apiURL(), "check-inventory", "--json", a, b}, tt.args...)
+			got := run(t, nil, args...)
+			var report Report
+			if err := json.Unmarshal([]byte(got.stdout), &report); err != nil {
+				t.Fatalf("parsing report: %s\nstderr: %s", err, got.stderr)
+			}
+			var sources []string
+			for _, r := range report.Results {
+				sources = append(sources, r.Source)
+			}
+			if !reflect.DeepEqual(sources, tt.sources) {
+				t.Errorf("sources = %q, want %q", sources, tt.sources)
+			}
+			if n := api.count("nodejs"); n != 1 {
+				t.Errorf("nodejs was fetched %d times, want once", n)
+			}
+		})
+	}
+}
+
+func TestTableSourceColumn(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	a := writeFile(t, "a.yaml", "- product: nodejs\n  version: \"22\"\n")
+	b := writeFile(t, "b.yaml", "- product: nodejs\n  version: \"22\"\n")
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"one file", []string{a}, "PRODUCT"},
+		{"several files", []string{a, b}, "SOURCE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "check-inventory", "--format", "table"}, tt.files...)
+			got := run(t, nil, args...)
+			lines := strings.Split(got.stdout, "\n")
+			if fields := strings.Fields(lines[0]); len(fields) == 0 || fields[0] != tt.want {
+				t.Fatalf("header = %q, want it to start with %s", lines[0], tt.want)
+			}
+			if len(tt.files) > 1 && !strings.HasPrefix(lines[1], a+", "+b) {
+				t.Errorf("row = %q, want it to start with both files", lines[1])
+			}
+		})
+	}
+}
+
+func TestGroupedFormat(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", `- product: nodejs
+  version: "22"
+- product: python
+  version: "3.12"
+- product: nodejs
+  version: "20"
+- product: nodejs
+  version: "18"
+- product: nodejs
+  version: "16"
+`)
+	want := strings.Join([]string{
+		"nodejs (4 versions)",
+		"  VERSION  STATUS       EOL",
+		"  22       supported    " + daysFromNow(100),
+		"  20       maintenance  " + daysFromNow(2000),
+		"  18       eol          2025-04-30",
+		"  16       error        Version not found",
+		"",
+		"python (1 version)",
+		"  VERSION  STATUS       EOL",
+		"  3.12     maintenance  " + daysFromNow(1500),
+	}, "\n") + "\n"
+
+	for _, args := range [][]string{{"--format", "grouped"}, {"--merge-duplicate-products"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "check-inventory", inventory}, args...)...)
+			if got.code != 1 {
+				t.Errorf("exit code = %d, want 1\nstderr: %s", got.code, got.stderr)
+			}
+			if got.stdout != want {
+				t.Errorf("stdout =\n%s\nwant\n%s", got.stdout, want)
+			}
+		})
+	}
+}
+
+func TestNoHeader(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"18\"\n- product: python\n  version: \"3.9\"\n")
+
+	tests := []struct {
+		format   string
+		noHeader bool
+		want     []string
+	}{
+		{"table", false, []string{
+			"PRODUCT  VERSION  STATUS  EOL         SUPPORT",
+			"nodejs   18       eol     2025-04-30  2023-10-18",
+			"python   3.9      eol     2025-10-31  2022-05-17",
+		}},
+		{"table", true, []string{
+			"nodejs  18   eol  2025-04-30  2023-10-18",
+			"python  3.9  eol  2025-10-31  2022-05-17",
+		}},
+		{"csv", false, []string{
+			"source,product,version,status,eol,support,error",
+			",nodejs,18,eol,2025-04-30,2023-10-18,",
+			",python,3.9,eol,2025-10-31,2022-05-17,",
+		}},
+		{"csv", true, []string{
+			",nodejs,18,eol,2025-04-30,2023-10-18,",
+			",python,3.9,eol,2025-10-31,2022-05-17,",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s no-header=%t", tt.format, tt.noHeader), func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", "--format", tt.format, fmt.Sprintf("--no-header=%t", tt.noHeader), inventory)
+			if want := strings.Join(tt.want, "\n") + "\n"; got.stdout != want {
+				t.Errorf("stdout =\n%s\nwant\n%s", got.stdout, want)
+			}
+		})
+	}
+}
+
+func TestInventoryKeyPaths(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	flat := writeFile(t, "versions.yaml", `- name: nodejs
+  tag: 22
+  owner: web
+- name: python
+  tag: 3.10
+`)
+	nested := writeFile(t, "versions.json", `[
+  {"component": {"name": "nodejs", "team": "web"}, "image": {"repo": "node", "tag": "18"}},
+  {"component": {"name": "python"}, "image": {"tag": "3.12"}}
+]`)
+
+	tests := []struct {
+		name string
+		file string
+		args []string
+		want []string
+	}{
+		{"flat keys", flat, []string{"--product-key", "name", "--version-key", "tag"}, []string{"nodejs 22", "python 3.10"}},
+		{"nested keys", nested, []string{"--product-key", "component.name", "--version-key", "image.tag"}, []string{"nodejs 18", "python 3.12"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "check-inventory", "--json", tt.file}, tt.args...)
+			got := run(t, nil, args...)
+			var report Report
+			if err := json.Unmarshal([]byte(got.stdout), &report); err != nil {
+				t.Fatalf("parsing report: %s\nstderr: %s", err, got.stderr)
+			}
+			var checked []string
+			for _, r := range report.Results {
+				checked = append(checked, r.Product+" "+r.Version)
+			}
+			if !reflect.DeepEqual(checked, tt.want) {
+				t.Errorf("checked %q, want %q", checked, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", nested, "--product-key", "component.name", "--version-key", "image.repo")
+		if want := "Error: Error parsing inventory file: entry 2 has no \"image.repo\"\n"; got.code != 1 || got.stderr != want {
+			t.Errorf("exit code %d, stderr %q, want %q", got.code, got.stderr, want)
+		}
+	})
+
+	t.Run("key pointing at a mapping", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "check-inventory", nested, "--product-key", "component", "--version-key", "image.tag")
+		if want := "Error: Error parsing inventory file: entry 1 has no \"component\"\n"; got.code != 1 || got.stderr != want {
+			t.Errorf("exit code %d, stderr %q, want %q", got.code, got.stderr, want)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var javaDistribution string
+
+// mavenJavaProperties are the pom.xml properties that set the Java version,
+// most specific first.
+var mavenJavaProperties = []string{"maven.compiler.release", "maven.compiler.source", "maven.compiler.target", "java.version"}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pomFile struct {
+	Properties struct {
+		Entries []pomProperty `xml:",any"`
+	} `xml:"properties"`
+}
+
+var mavenPropertyRef = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+
+// gradleJavaSettings matches the Gradle settings that set the Java version,
+// in Groovy and Kotlin DSL build scripts as well as gradle.properties.
+var gradleJavaSettings = []*regexp.Regexp{
+	regexp.MustCompile(`JavaLanguageVersion\.of\(\s*"?(\d+)"?\s*\)`),
+	regexp.MustCompile(`\b(?:source|target)Compatibility\s*[=:]?\s*(?:JavaVersion\.VERSION_)?['"]?([0-9._]+)['"]?`),
+}
+
+// normalizeJavaVersion turns the ways build files spell a Java version
+// (1.8, 1_8, 17) into the cycle names endoflife.date uses (8, 17).
+func normalizeJavaVersion(version string) string {
+	version = strings.ReplaceAll(version, "_", ".")
+	if rest, ok := strings.CutPrefix(version, "1."); ok {
+		return rest
+	}
+	return version
+}
+
+// mavenJavaVersion reads the Java version of a pom.xml, resolving a property
+// that only refers to another one, e.g. <maven.compiler.release>${java.version}.
+func mavenJavaVersion(data []byte) (string, string, error) {
+	var pom pomFile
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return "", "", err
+	}
+	properties := map[string]string{}
+	for _, entry := range pom.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+
+	for _, name := range mavenJavaProperties {
+		value, ok := properties[name]
+		if !ok {
+			continue
+		}
+		if ref := mavenPropertyRef.FindStringSubmatch(value); ref != nil {
+			value = properties[ref[1]]
+		}
+		if value != "" {
+			return name, normalizeJavaVersion(value), nil
+		}
+	}
+	return "", "", nil
+}
+
+// gradleJavaVersion reads the Java version of a Gradle build script or
+// gradle.properties, preferring a toolchain over the compatibility settings.
+func gradleJavaVersion(data []byte) (string, string, error) {
+	for _, setting := range gradleJavaSettings {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if match := setting.FindStringSubmatch(line); match != nil {
+				return fmt.Sprintf("line %d", n), normalizeJavaVersion(match[1]), nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", "", err
+		}
+	}
+	return "", "", nil
+}
+
+// checkJavaCmd represents the check-java command
+var checkJavaCmd = &cobra.Command{
+	Use:         "check-java <path-to-pom.xml-or-build.gradle>",
+	Annotations: map[string]string{inputsAnnotation: "pom.xml,build.gradle,build.gradle.kts,gradle.properties"},
+	Short:       "Check the Java version of a Maven or Gradle build for EOL",
+	Long: `Check the Java version a Maven pom.xml (maven.compiler.release, .source or
+.target, java.version) or a Gradle build.gradle, build.gradle.kts or
+gradle.properties (toolchain languageVersion, sourceCompatibility or
+targetCompatibility) builds for, against the JDK distribution given with
+--distribution.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Error reading build file: %s", err)
+		}
+
+		var source, version string
+		if strings.EqualFold(filepath.Ext(path), ".xml") {
+			source, version, err = mavenJavaVersion(data)
+		} else {
+			source, version, err = gradleJavaVersion(data)
+		}
+		if err != nil {
+			return fmt.Errorf("Error parsing build file: %s", err)
+		}
+		if version == "" {
+			return fmt.Errorf("No Java version found in %s", path)
+		}
+
+		refs := []checkRef{{
+			Source:  source,
+			Label:   fmt.Sprintf("Java %s", version),
+			Product: javaDistribution,
+			Version: version,
+		}}
+		return checkRefs(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkJavaCmd)
+
+	checkJavaCmd.Flags().StringVarP(&javaDistribution, "distribution", "d", "eclipse-temurin", "endoflife.date product of the JDK you run, e.g. oracle-jdk, amazon-corretto or eclipse-temurin")
+	addMinRemainingFlag(checkJavaCmd)
+	addRemediationFlag(checkJavaCmd)
+	addJSONFlags(checkJavaCmd, "the report of the Java version")
+	addPlanFlag(checkJavaCmd)
+}
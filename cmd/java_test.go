@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMavenJavaVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		pom      string
+		property string
+		version  string
+	}{
+		{"release", `<project><properties><maven.compiler.source>11</maven.compiler.source><maven.compiler.release>17</maven.compiler.release></properties></project>`, "maven.compiler.release", "17"},
+		{"property reference", `<project><properties><java.version>21</java.version><maven.compiler.source>${java.version}</maven.compiler.source></properties></project>`, "maven.compiler.source", "21"},
+		{"legacy spelling", `<project><properties><maven.compiler.target> 1.8 </maven.compiler.target></properties></project>`, "maven.compiler.target", "8"},
+		{"no java version", `<project><properties><project.build.sourceEncoding>UTF-8</project.build.sourceEncoding></properties></project>`, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property, version, err := mavenJavaVersion([]byte(tt.pom))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if property != tt.property || version != tt.version {
+				t.Errorf("got %q, %q, want %q, %q", property, version, tt.property, tt.version)
+			}
+		})
+	}
+}
+
+func TestGradleJavaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   string
+		source  string
+		version string
+	}{
+		{"groovy compatibility", "plugins { id 'java' }\nsourceCompatibility = '1.8'\n", "line 2", "8"},
+		{"kotlin JavaVersion", "java {\n    sourceCompatibility = JavaVersion.VERSION_17\n}\n", "line 2", "17"},
+		{"toolchain wins", "targetCompatibility = 11\njava {\n    toolchain {\n        languageVersion = JavaLanguageVersion.of(21)\n    }\n}\n", "line 4", "21"},
+		{"gradle.properties", "# sourceCompatibility=1.7\ntargetCompatibility=1_8\n", "line 2", "8"},
+		{"no java version", "plugins { id 'java' }\n", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, version, err := gradleJavaVersion([]byte(tt.build))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source != tt.source || version != tt.version {
+				t.Errorf("got %q, %q, want %q, %q", source, version, tt.source, tt.version)
+			}
+		})
+	}
+}
+
+func TestCheckJava(t *testing.T) {
+	products := testProducts()
+	products["eclipse-temurin"] = `[
+{"cycle":"21","releaseDate":"2023-09-19","lts":true,"eol":"` + daysFromNow(1000) + `","latest":"21.0.5+11"},
+{"cycle":"8","releaseDate":"2014-03-18","lts":true,"eol":"` + daysFromNow(900) + `","latest":"8u432-b06"}
+]`
+	products["amazon-corretto"] = `[{"cycle":"8","releaseDate":"2019-01-31","eol":"2025-10-31","latest":"8.432.06.1"}]`
+	api := newAPIServer(t, products)
+
+	dir := t.TempDir()
+	pom := filepath.Join(dir, "pom.xml")
+	if err := os.WriteFile(pom, []byte(`<project><properties><maven.compiler.release>1.8</maven.compiler.release></properties></project>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gradle := filepath.Join(dir, "build.gradle.kts")
+	if err := os.WriteFile(gradle, []byte("java {\n    toolchain {\n        languageVersion.set(JavaLanguageVersion.of(21))\n    }\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"maven", []string{pom}, 0, "maven.compiler.release: Eclipse-temurin 8 is not EOL yet. It will be EOL on " + daysFromNow(900) + "\n", ""},
+		{"maven on corretto", []string{"--distribution", "amazon-corretto", pom}, 1, "maven.compiler.release: Amazon-corretto 8 is EOL since 2025-10-31\n", ""},
+		{"gradle", []string{gradle}, 0, "line 3: Eclipse-temurin 21 is not EOL yet. It will be EOL on " + daysFromNow(1000) + "\n", ""},
+		{"no java version", []string{writeFile(t, "build.gradle", "plugins { id 'java' }\n")}, 1, "", "No Java version found in "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "check-java"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if got.stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", got.stdout, tt.stdout)
+			}
+			if tt.stderr != "" && !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var k8sVersion string
+var fromKubectl bool
+
+// normalizeK8sVersion turns versions like "v1.27.3" or "1.27.3-eks-2d98532"
+// into the "1.27" cycle form endoflife.date uses.
+func normalizeK8sVersion(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "+")
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("Invalid Kubernetes version %q", version)
+	}
+	return parts[0] + "." + parts[1], nil
+}
+
+// kubectlServerVersion asks kubectl for the version of the cluster it is
+// currently pointed at.
+func kubectlServerVersion() (string, error) {
+	out, err := exec.Command("kubectl", "version", "-o", "json").Output()
+	if err != nil {
+		return "", fmt.Errorf("Error running kubectl: %s", err)
+	}
+	var info struct {
+		ServerVersion *struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", fmt.Errorf("Error parsing kubectl output: %s", err)
+	}
+	if info.ServerVersion == nil {
+		return "", errors.New("kubectl didn't report a server version")
+	}
+	return info.ServerVersion.GitVersion, nil
+}
+
+// checkK8sCmd represents the check-k8s command
+var checkK8sCmd = &cobra.Command{
+	Use:         "check-k8s",
+	Annotations: map[string]string{inputsAnnotation: "kubernetes-version,kubectl"},
+	Short:       "Check if a Kubernetes cluster version is EOL",
+	Args:        cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := k8sVersion
+		if fromKubectl {
+			detected, err := kubectlServerVersion()
+			if err != nil {
+				return err
+			}
+			version = detected
+		}
+		if version == "" {
+			return errors.New("Either --version or --from-kubectl is required")
+		}
+
+		cycle, err := normalizeK8sVersion(version)
+		if err != nil {
+			return err
+		}
+
+		v, provenance, err := CheckVersion(cmd.Context(), "kubernetes", cycle)
+		if err != nil {
+			return err
+		}
+		result := newResult("kubernetes", cycle, v, provenance)
+		if jsonOutput {
+			if err := printJSON(result); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println(describeResult(result))
+		}
+		if result.IsEOL {
+			return errEOL
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkK8sCmd)
+
+	checkK8sCmd.Flags().StringVar(&k8sVersion, "version", "", "Kubernetes version to check, e.g. 1.27 or v1.27.3")
+	checkK8sCmd.Flags().BoolVar(&fromKubectl, "from-kubectl", false, "Detect the version of the current cluster with kubectl")
+	checkK8sCmd.MarkFlagsMutuallyExclusive("version", "from-kubectl")
+	addJSONFlags(checkK8sCmd, "the result")
+}
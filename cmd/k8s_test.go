@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeK8sVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"1.27", "1.27", false},
+		{"v1.27.3", "1.27", false},
+		{" 1.29.1 ", "1.29", false},
+		{"v1.27.3-eks-2d98532", "1.27", false},
+		{"v1.28.2+k3s1", "1.28", false},
+		{"1", "", true},
+		{"v1.", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeK8sVersion(tt.version)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeK8sVersion(%q) = %q, %v, want %q (error: %t)", tt.version, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckK8s(t *testing.T) {
+	products := testProducts()
+	products["kubernetes"] = `[
+{"cycle":"1.31","releaseDate":"2024-08-13","support":"` + daysFromNow(50) + `","eol":"` + daysFromNow(300) + `","latest":"1.31.1"},
+{"cycle":"1.27","releaseDate":"2023-04-11","support":"2024-04-28","eol":"2024-06-28","latest":"1.27.16"}
+]`
+	api := newAPIServer(t, products)
+
+	kubectl := t.TempDir()
+	script := "#!/bin/sh\necho '{\"clientVersion\":{\"gitVersion\":\"v1.31.0\"},\"serverVersion\":{\"gitVersion\":\"v1.27.3-eks-2d98532\"}}'\n"
+	if err := os.WriteFile(filepath.Join(kubectl, "kubectl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"supported", []string{"--version", "v1.31.1"}, 0, "Kubernetes 1.31 is not EOL yet. It will be EOL on " + daysFromNow(300) + "\n", ""},
+		{"eol", []string{"--version", "1.27"}, 1, "Kubernetes 1.27 is EOL since 2024-06-28\n", ""},
+		{"from kubectl", []string{"--from-kubectl"}, 1, "Kubernetes 1.27 is EOL since 2024-06-28\n", ""},
+		{"no version", nil, 1, "", "Error: Either --version or --from-kubectl is required\n"},
+		{"invalid version", []string{"--version", "v1"}, 1, "", "Error: Invalid Kubernetes version \"1\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := []string{"PATH=" + kubectl + string(os.PathListSeparator) + os.Getenv("PATH")}
+			got := run(t, env, append([]string{"--api-url", api.apiURL(), "check-k8s"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if got.stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", got.stdout, tt.stdout)
+			}
+			if tt.stderr != "" && !strings.HasSuffix(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
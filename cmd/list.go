@@ -0,0 +1,218 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+var recentCycles int
+var eolBetween string
+var statusMatrix bool
+
+// Cycle is the JSON form of a release cycle in list output.
+type Cycle struct {
+	Cycle       string `json:"cycle"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Support     string `json:"support,omitempty"`
+	EOL         string `json:"eol,omitempty"`
+	Latest      string `json:"latest,omitempty"`
+	Status      Status `json:"status"`
+}
+
+func newCycle(v SoftwareVersion, now string) Cycle {
+	return Cycle{
+		Cycle:       v.Cycle,
+		ReleaseDate: v.ReleaseDate,
+		Support:     endoflife.SupportEndDate(v),
+		EOL:         endoflife.EOLDate(v),
+		Latest:      v.Latest,
+		Status:      endoflife.CycleStatus(v, now),
+	}
+}
+
+// MatrixCell says whether a cycle has reached a point in its lifecycle, and
+// when it did or will, if that's known.
+type MatrixCell struct {
+	Reached bool   `json:"reached"`
+	Date    string `json:"date,omitempty"`
+}
+
+// MatrixRow is one cycle of a --status-matrix.
+type MatrixRow struct {
+	Cycle     string     `json:"cycle"`
+	Released  MatrixCell `json:"released"`
+	Supported MatrixCell `json:"supported"`
+	EOL       MatrixCell `json:"eol"`
+}
+
+// newMatrixRow works out where a cycle is in its lifecycle. Unlike the other
+// two, Supported is reached while the cycle still gets regular updates.
+func newMatrixRow(v SoftwareVersion, now string) MatrixRow {
+	supportDate, _ := v.Support.(string)
+	if support, ok := v.Support.(bool); ok && support {
+		supportDate = endoflife.EOLDate(v)
+	}
+	return MatrixRow{
+		Cycle:     v.Cycle,
+		Released:  MatrixCell{Reached: v.ReleaseDate != "" && v.ReleaseDate <= now, Date: v.ReleaseDate},
+		Supported: MatrixCell{Reached: !supportEnded(v, now), Date: supportDate},
+		EOL:       MatrixCell{Reached: endoflife.IsEOL(v, now), Date: endoflife.EOLDate(v)},
+	}
+}
+
+func (c MatrixCell) String() string {
+	mark := "✗"
+	if c.Reached {
+		mark = "✓"
+	}
+	if c.Date == "" {
+		return mark
+	}
+	return mark + " " + c.Date
+}
+
+// parseDateRange parses a "YYYY-MM-DD..YYYY-MM-DD" window.
+func parseDateRange(window string) (time.Time, time.Time, error) {
+	fromText, toText, ok := strings.Cut(window, "..")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid date range %q, expected FROM..TO", window)
+	}
+	from, err := time.Parse("2006-01-02", fromText)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid start date %q, expected YYYY-MM-DD", fromText)
+	}
+	to, err := time.Parse("2006-01-02", toText)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid end date %q, expected YYYY-MM-DD", toText)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid date range %q, the end is before the start", window)
+	}
+	return from, to, nil
+}
+
+// eolInRange returns the cycles whose EOL date falls within [from, to].
+// Cycles without an EOL date are left out.
+func eolInRange(versions []SoftwareVersion, from time.Time, to time.Time) []SoftwareVersion {
+	var matching []SoftwareVersion
+	for _, v := range versions {
+		eol, err := time.Parse("2006-01-02", endoflife.EOLDate(v))
+		if err != nil {
+			continue
+		}
+		if !eol.Before(from) && !eol.After(to) {
+			matching = append(matching, v)
+		}
+	}
+	return matching
+}
+
+// mostRecent returns the n cycles with the latest release dates, newest
+// first. Cycles without a parseable release date sort last.
+func mostRecent(versions []SoftwareVersion, n int) []SoftwareVersion {
+	released := func(v SoftwareVersion) time.Time {
+		date, _ := time.Parse("2006-01-02", v.ReleaseDate)
+		return date
+	}
+	sorted := append([]SoftwareVersion(nil), versions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return released(sorted[i]).After(released(sorted[j]))
+	})
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list <name>",
+	Short: "List all release cycles of a product",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		var from, to time.Time
+		if eolBetween != "" {
+			var err error
+			if from, to, err = parseDateRange(eolBetween); err != nil {
+				return err
+			}
+		}
+
+		versions, _, err := fetchCycles(cmd.Context(), name)
+		if err != nil {
+			return err
+		}
+		if eolBetween != "" {
+			versions = eolInRange(versions, from, to)
+		}
+		if recentCycles > 0 {
+			versions = mostRecent(versions, recentCycles)
+		}
+
+		now := today()
+		if statusMatrix {
+			return printStatusMatrix(versions, now)
+		}
+		if jsonOutput {
+			cycles := make([]Cycle, len(versions))
+			for i, v := range versions {
+				cycles[i] = newCycle(v, now)
+			}
+			return printJSON(cycles)
+		}
+
+		out, done := startPager()
+		defer done()
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CYCLE\tRELEASED\tSUPPORT\tEOL\tLATEST\tSTATUS")
+		for _, v := range versions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Cycle, v.ReleaseDate, endoflife.SupportEndDate(v), eolCell(v), v.Latest, endoflife.CycleStatus(v, now))
+		}
+		return w.Flush()
+	},
+}
+
+// printStatusMatrix prints whether each cycle is released, still supported
+// and EOL, with the dates of each.
+func printStatusMatrix(versions []SoftwareVersion, now string) error {
+	rows := make([]MatrixRow, len(versions))
+	for i, v := range versions {
+		rows[i] = newMatrixRow(v, now)
+	}
+	if jsonOutput {
+		return printJSON(rows)
+	}
+
+	out, done := startPager()
+	defer done()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CYCLE\tRELEASED\tSUPPORTED\tEOL")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", row.Cycle, row.Released, row.Supported, row.EOL)
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().IntVar(&recentCycles, "recent", 0, "Only show the N most recently released cycles")
+	listCmd.Flags().StringVar(&eolBetween, "eol-between", "", "Only show cycles whose EOL date falls within FROM..TO (YYYY-MM-DD..YYYY-MM-DD)")
+	listCmd.Flags().BoolVar(&statusMatrix, "status-matrix", false, "Show whether each cycle is released, supported and EOL at a glance")
+	listCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the cycles as JSON")
+	listCmd.Flags().BoolVar(&noPager, "no-pager", false, "Don't pipe the output through $PAGER")
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// debianCycles are listed out of release order, with one cycle that has
+// neither a release date nor an EOL date.
+const debianCycles = `[
+{"cycle":"10","releaseDate":"2019-07-06","eol":"2022-09-10"},
+{"cycle":"12","releaseDate":"2023-06-10","eol":"2026-06-10"},
+{"cycle":"9","releaseDate":"2017-06-17","eol":"2020-07-18"},
+{"cycle":"13","releaseDate":"2025-08-09","eol":false},
+{"cycle":"11","releaseDate":"2021-08-14","eol":"2024-08-14"},
+{"cycle":"sid","eol":false}
+]`
+
+func TestListRecent(t *testing.T) {
+	products := testProducts()
+	products["debian"] = debianCycles
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		recent string
+		want   []string
+	}{
+		{"1", []string{"13"}},
+		{"3", []string{"13", "12", "11"}},
+		{"10", []string{"13", "12", "11", "10", "9", "sid"}},
+		{"0", []string{"10", "12", "9", "13", "11", "sid"}},
+	}
+	for _, tt := range tests {
+		t.Run("--recent "+tt.recent, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "list", "debian", "--json", "--recent", tt.recent)
+			if got.code != 0 {
+				t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+			}
+			var cycles []Cycle
+			if err := json.Unmarshal([]byte(got.stdout), &cycles); err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, len(cycles))
+			for i, c := range cycles {
+				names[i] = c.Cycle
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("cycles = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestListEOLBetween(t *testing.T) {
+	products := testProducts()
+	products["debian"] = debianCycles
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		window string
+		want   []string
+	}{
+		{"2022-01-01..2024-12-31", []string{"10", "11"}},
+		{"2022-09-10..2024-08-14", []string{"10", "11"}},
+		{"2022-09-11..2024-08-13", []string{}},
+		{"2026-01-01..2026-12-31", []string{"12"}},
+		{"2000-01-01..2099-12-31", []string{"10", "12", "9", "11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "list", "debian", "--json", "--eol-between", tt.window)
+			if got.code != 0 {
+				t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+			}
+			var cycles []Cycle
+			if err := json.Unmarshal([]byte(got.stdout), &cycles); err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, c := range cycles {
+				names = append(names, c.Cycle)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("cycles = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateRangeErrors(t *testing.T) {
+	tests := []struct {
+		window string
+		want   string
+	}{
+		{"2025-01-01", `Invalid date range "2025-01-01", expected FROM..TO`},
+		{"2025..2025-12-31", `Invalid start date "2025", expected YYYY-MM-DD`},
+		{"2025-01-01..next year", `Invalid end date "next year", expected YYYY-MM-DD`},
+		{"2025-12-31..2025-01-01", `Invalid date range "2025-12-31..2025-01-01", the end is before the start`},
+	}
+	for _, tt := range tests {
+		if _, _, err := parseDateRange(tt.window); err == nil || err.Error() != tt.want {
+			t.Errorf("parseDateRange(%q) = %v, want %q", tt.window, err, tt.want)
+		}
+	}
+}
+
+func TestNewMatrixRow(t *testing.T) {
+	const now = "2025-06-01"
+	tests := []struct {
+		name string
+		v    SoftwareVersion
+		want MatrixRow
+	}{
+		{"not released yet", SoftwareVersion{Cycle: "26", ReleaseDate: "2026-04-01", Support: "2026-10-01", EOL: "2029-04-30"}, MatrixRow{
+			Cycle: "26", Released: MatrixCell{false, "2026-04-01"}, Supported: MatrixCell{true, "2026-10-01"}, EOL: MatrixCell{false, "2029-04-30"},
+		}},
+		{"supported", SoftwareVersion{Cycle: "24", ReleaseDate: "2025-05-06", Support: "2025-10-20", EOL: "2028-04-30"}, MatrixRow{
+			Cycle: "24", Released: MatrixCell{true, "2025-05-06"}, Supported: MatrixCell{true, "2025-10-20"}, EOL: MatrixCell{false, "2028-04-30"},
+		}},
+		{"supported until EOL", SoftwareVersion{Cycle: "2", ReleaseDate: "2024-10-09", Support: true, EOL: "2026-01-01"}, MatrixRow{
+			Cycle: "2", Released: MatrixCell{true, "2024-10-09"}, Supported: MatrixCell{true, "2026-01-01"}, EOL: MatrixCell{false, "2026-01-01"},
+		}},
+		{"maintenance", SoftwareVersion{Cycle: "22", ReleaseDate: "2024-04-24", Support: "2025-04-01", EOL: "2027-04-30"}, MatrixRow{
+			Cycle: "22", Released: MatrixCell{true, "2024-04-24"}, Supported: MatrixCell{false, "2025-04-01"}, EOL: MatrixCell{false, "2027-04-30"},
+		}},
+		{"eol", SoftwareVersion{Cycle: "18", ReleaseDate: "2022-04-19", Support: "2023-10-18", EOL: "2025-04-30"}, MatrixRow{
+			Cycle: "18", Released: MatrixCell{true, "2022-04-19"}, Supported: MatrixCell{false, "2023-10-18"}, EOL: MatrixCell{true, "2025-04-30"},
+		}},
+		{"no dates", SoftwareVersion{Cycle: "sid", Support: false, EOL: false}, MatrixRow{
+			Cycle: "sid", Released: MatrixCell{false, ""}, Supported: MatrixCell{false, ""}, EOL: MatrixCell{false, ""},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newMatrixRow(tt.v, now); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListStatusMatrix(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	got := run(t, nil, "--api-url", api.apiURL(), "list", "nodejs", "--status-matrix")
+	want := strings.Join([]string{
+		"CYCLE  RELEASED      SUPPORTED     EOL",
+		"22     ✓ 2024-04-24  ✓ " + daysFromNow(50) + "  ✗ " + daysFromNow(100),
+		"20     ✓ 2023-04-18  ✗ 2024-10-22  ✗ " + daysFromNow(2000),
+		"18     ✓ 2022-04-19  ✗ 2023-10-18  ✓ 2025-04-30",
+	}, "\n") + "\n"
+	if got.code != 0 || got.stdout != want {
+		t.Errorf("exit code %d, stdout =\n%s\nwant\n%s", got.code, got.stdout, want)
+	}
+
+	got = run(t, nil, "--api-url", api.apiURL(), "list", "nodejs", "--status-matrix", "--json", "--recent", "1")
+	var rows []MatrixRow
+	if err := json.Unmarshal([]byte(got.stdout), &rows); err != nil {
+		t.Fatalf("parsing %q: %s", got.stdout, err)
+	}
+	wantRows := []MatrixRow{{Cycle: "22", Released: MatrixCell{true, "2024-04-24"}, Supported: MatrixCell{true, daysFromNow(50)}, EOL: MatrixCell{false, daysFromNow(100)}}}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("rows = %+v, want %+v", rows, wantRows)
+	}
+}
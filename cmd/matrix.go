@@ -0,0 +1,214 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// toolVersionsProducts maps asdf plugin names to endoflife.date products
+// where they differ.
+var toolVersionsProducts = map[string]string{
+	"node":   "nodejs",
+	"golang": "go",
+}
+
+var matrixProducts []string
+var versionFrom string
+
+// toolVersion is a product pinned in a .tool-versions file. When a line lists
+// several versions, the first one is the one in use and the others are
+// fallbacks, used when it isn't installed.
+type toolVersion struct {
+	Product  string
+	Versions []string
+}
+
+// readToolVersions reads the product versions an asdf .tool-versions file
+// pins, in file order.
+func readToolVersions(path string) ([]toolVersion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading version file: %s", err)
+	}
+
+	var pinned []toolVersion
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		product := fields[0]
+		if mapped, ok := toolVersionsProducts[product]; ok {
+			product = mapped
+		}
+		pinned = append(pinned, toolVersion{Product: product, Versions: fields[1:]})
+	}
+	return pinned, scanner.Err()
+}
+
+// matrixRows picks the requested products out of the pinned ones. A
+// requested product that isn't pinned comes back without versions.
+func matrixRows(pinned []toolVersion, products []string) []toolVersion {
+	if len(products) == 0 {
+		return pinned
+	}
+	var rows []toolVersion
+	for _, product := range products {
+		if mapped, ok := toolVersionsProducts[product]; ok {
+			product = mapped
+		}
+		row := toolVersion{Product: product}
+		for _, p := range pinned {
+			if p.Product == product {
+				row = p
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeMatrix renders the grid of check-matrix: a row per product, with the
+// version in use and then its fallbacks in columns.
+func writeMatrix(w io.Writer, rows []toolVersion, results []Result) error {
+	checked := map[string]Result{}
+	for _, r := range results {
+		checked[r.Product+"\x00"+r.Version] = r
+	}
+	columns := 1
+	for _, row := range rows {
+		columns = max(columns, len(row.Versions))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if !noHeader {
+		fmt.Fprint(tw, "PRODUCT\tIN USE")
+		for i := 1; i < columns; i++ {
+			if columns == 2 {
+				fmt.Fprint(tw, "\tFALLBACK")
+			} else {
+				fmt.Fprintf(tw, "\tFALLBACK %d", i)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	for _, row := range rows {
+		cells := []string{row.Product}
+		if len(row.Versions) == 0 {
+			cells = append(cells, "not pinned in "+versionFrom)
+		}
+		for _, version := range row.Versions {
+			r, ok := checked[row.Product+"\x00"+version]
+			if !ok {
+				cells = append(cells, version+": not checked")
+				continue
+			}
+			cells = append(cells, matrixCell(r))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// matrixCell renders a checked version for the grid, e.g.
+// "18: eol since 2025-04-30".
+func matrixCell(r Result) string {
+	switch {
+	case r.Error != "":
+		return fmt.Sprintf("%s: %s", r.Version, r.Error)
+	case r.EOL == "":
+		return fmt.Sprintf("%s: %s", r.Version, r.Status)
+	case r.IsEOL:
+		return fmt.Sprintf("%s: eol since %s", r.Version, r.EOL)
+	default:
+		return fmt.Sprintf("%s: %s until %s", r.Version, r.Status, r.EOL)
+	}
+}
+
+// checkMatrixCmd represents the check-matrix command
+var checkMatrixCmd = &cobra.Command{
+	Use:         "check-matrix",
+	Annotations: map[string]string{inputsAnnotation: ".tool-versions"},
+	Short:       "Check the versions a polyglot repository pins for several products",
+	Long: `Check the versions a polyglot repository pins for several products at once,
+reading them from an asdf .tool-versions file, and print them as a grid with
+a row per product and a column for the version in use and each fallback:
+
+  date-reaper check-matrix --products nodejs,python --version-from .tool-versions
+
+Without --products every product in the file is checked. Fallback versions
+are checked too, since they are what runs when the first one isn't
+installed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pinned, err := readToolVersions(versionFrom)
+		if err != nil {
+			return err
+		}
+		rows := matrixRows(pinned, matrixProducts)
+
+		var missing []Result
+		var toCheck []InventoryItem
+		for _, row := range rows {
+			if len(row.Versions) == 0 {
+				missing = append(missing, Result{Product: row.Product, Error: fmt.Sprintf("not pinned in %s", versionFrom)})
+				continue
+			}
+			for _, version := range row.Versions {
+				toCheck = append(toCheck, InventoryItem{Product: row.Product, Version: version})
+			}
+		}
+		if plan {
+			showPlan(toCheck)
+			return nil
+		}
+
+		results, incomplete := evaluateItems(cmd.Context(), toCheck)
+		results = append(results, missing...)
+
+		verdict := bulkVerdict(results, incomplete)
+		if verdict == nil && len(missing) > 0 {
+			verdict = fmt.Errorf("%d product(s) are not pinned in %s", len(missing), versionFrom)
+		}
+		report := newReport(results, verdict)
+		if jsonOutput {
+			err = writeJSON(os.Stdout, report)
+		} else {
+			err = writeMatrix(os.Stdout, rows, results)
+		}
+		if err != nil {
+			return err
+		}
+		remediate(cmd.Context(), results, verdict)
+		return verdict
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkMatrixCmd)
+
+	checkMatrixCmd.Flags().StringSliceVarP(&matrixProducts, "products", "p", nil, "Products to check, comma-separated (default all products in the version file)")
+	checkMatrixCmd.Flags().StringVar(&versionFrom, "version-from", ".tool-versions", "File the versions are pinned in, in the asdf .tool-versions format")
+	checkMatrixCmd.Flags().StringSliceVar(&acceptStates, "accept", nil, "Only pass if every version is in one of these states: supported, maintenance, unknown, eol, soon")
+	checkMatrixCmd.Flags().IntVar(&soonDays, "soon-days", 30, "Number of days before EOL a version is considered to be EOL soon")
+	checkMatrixCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "Number of products to check at the same time")
+	checkMatrixCmd.Flags().BoolVar(&noHeader, "no-header", false, "Leave out the header row of the grid")
+	addMinRemainingFlag(checkMatrixCmd)
+	addRemediationFlag(checkMatrixCmd)
+	addJSONFlags(checkMatrixCmd, "the report of the pinned versions")
+	addPlanFlag(checkMatrixCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckMatrixGrid(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	toolVersions := writeFile(t, ".tool-versions", "node 22 20 18 # fallbacks\n\npython 3.12\nruby 3.3\n")
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		grid [][]string
+	}{
+		{"every pinned product", nil, 1, [][]string{
+			{"PRODUCT", "IN USE", "FALLBACK 1", "FALLBACK 2"},
+			{"nodejs", "22: supported until " + daysFromNow(100), "20: maintenance until " + daysFromNow(2000), "18: eol since 2025-04-30"},
+			{"python", "3.12: maintenance until " + daysFromNow(1500)},
+			{"ruby", "3.3: Error: Server returned status 404"},
+		}},
+		{"requested products", []string{"--products", "python,node,go", "--no-header"}, 1, [][]string{
+			{"python", "3.12: maintenance until " + daysFromNow(1500)},
+			{"nodejs", "22: supported until " + daysFromNow(100), "20: maintenance until " + daysFromNow(2000), "18: eol since 2025-04-30"},
+			{"go", "not pinned in " + toolVersions},
+		}},
+		{"passing", []string{"--products", "python"}, 0, [][]string{
+			{"PRODUCT", "IN USE"},
+			{"python", "3.12: maintenance until " + daysFromNow(1500)},
+		}},
+	}
+	cells := regexp.MustCompile(`\s{2,}`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "--no-embedded", "--retries", "0", "check-matrix", "--version-from", toolVersions}, tt.args...)
+			got := run(t, nil, args...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			lines := strings.Split(strings.TrimSuffix(got.stdout, "\n"), "\n")
+			if len(lines) != len(tt.grid) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.grid), got.stdout)
+			}
+			for i, line := range lines {
+				if row := cells.Split(strings.TrimSpace(line), -1); !slices.Equal(row, tt.grid[i]) {
+					t.Errorf("row %d = %q, want %q", i, row, tt.grid[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// metric is a gauge exposed per checked product and version.
+type metric struct {
+	name  string
+	help  string
+	value func(r Result) (float64, bool)
+}
+
+var metrics = []metric{
+	{
+		name: "date_reaper_days_until_eol",
+		help: "Days until the version reaches EOL, negative once it has.",
+		value: func(r Result) (float64, bool) {
+			days, ok := daysUntil(r.EOL)
+			return float64(days), ok
+		},
+	},
+	{
+		name: "date_reaper_is_eol",
+		help: "Whether the version is EOL (1) or not (0).",
+		value: func(r Result) (float64, bool) {
+			if r.IsEOL {
+				return 1, true
+			}
+			return 0, true
+		},
+	},
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeOpenMetrics writes a one-off snapshot of the metrics in the
+// OpenMetrics text format, e.g. for pushing to a Pushgateway. A product
+// version listed several times is written once, since samples with the same
+// labels aren't allowed (and would have the same values anyway).
+func writeOpenMetrics(w io.Writer, report Report) error {
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# TYPE %s gauge\n", m.name)
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		written := map[[2]string]bool{}
+		for _, r := range report.Results {
+			if r.Error != "" || written[[2]string{r.Product, r.Version}] {
+				continue
+			}
+			written[[2]string{r.Product, r.Version}] = true
+			value, ok := m.value(r)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "%s{product=\"%s\",version=\"%s\"} %g\n", m.name, labelEscaper.Replace(r.Product), labelEscaper.Replace(r.Version), value)
+		}
+	}
+	_, err := fmt.Fprintln(w, "# EOF")
+	return err
+}
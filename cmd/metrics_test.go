@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	report := Report{Results: []Result{
+		{Source: "a.yaml", Product: "nodejs", Version: "18", EOL: "2025-04-30", Status: endoflife.StatusEOL, IsEOL: true},
+		{Source: "b.yaml", Product: "nodejs", Version: "18", EOL: "2025-04-30", Status: endoflife.StatusEOL, IsEOL: true},
+		{Product: "python", Version: "3.12", EOL: daysFromNow(10), Status: endoflife.StatusSupported},
+		{Product: "go", Version: "1.9", Error: "Version not found"},
+	}}
+
+	var out strings.Builder
+	if err := writeOpenMetrics(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	samples := map[string]int{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		series, _, _ := strings.Cut(line, " ")
+		samples[series]++
+	}
+
+	want := map[string]int{
+		`date_reaper_days_until_eol{product="nodejs",version="18"}`:   1,
+		`date_reaper_days_until_eol{product="python",version="3.12"}`: 1,
+		`date_reaper_is_eol{product="nodejs",version="18"}`:           1,
+		`date_reaper_is_eol{product="python",version="3.12"}`:         1,
+	}
+	if len(samples) != len(want) {
+		t.Errorf("got series %v, want %v", samples, want)
+	}
+	for series, n := range want {
+		if samples[series] != n {
+			t.Errorf("%s written %d time(s), want %d", series, samples[series], n)
+		}
+	}
+	if !strings.HasSuffix(out.String(), "# EOF\n") {
+		t.Error("output doesn't end with # EOF")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// noNetworkOnCacheHit makes a run fail with exitNetworkUsed if any API
+// request went out, to prove in tests and air-gapped setups that the cache
+// covered everything.
+var noNetworkOnCacheHit bool
+
+// networkRequests counts the API requests made in this run, retries included.
+var networkRequests atomic.Int64
+
+// networkUseError logs how many API requests the run made and, with
+// --no-network-on-cache-hit, returns an error if there were any.
+func networkUseError() error {
+	n := networkRequests.Load()
+	logf("%d network request(s) made", n)
+	if !noNetworkOnCacheHit || n == 0 {
+		return nil
+	}
+	return &exitError{
+		code: exitNetworkUsed,
+		err:  fmt.Errorf("%d network request(s) were made, but --no-network-on-cache-hit expects the cache to cover everything", n),
+	}
+}
+
+// networkSettled is set once networkVerdict has decided the run's verdict.
+var networkSettled bool
+
+// networkVerdict turns the verdict of a run that made API requests despite
+// --no-network-on-cache-hit into exitNetworkUsed, whatever else happened.
+// Commands that report their verdict call it before building the report, so
+// the report has the code the run ends with; requests made after that, e.g.
+// for the remediation checklist, don't change it anymore.
+func networkVerdict(verdict error) error {
+	if networkSettled {
+		return verdict
+	}
+	networkSettled = true
+	if err := networkUseError(); err != nil {
+		return err
+	}
+	return verdict
+}
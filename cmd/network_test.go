@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNoNetworkOnCacheHit(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	env := []string{"DATE_REAPER_CACHE_DIR=" + t.TempDir()}
+
+	// The steps share a cache, which the first one fills.
+	steps := []struct {
+		name     string
+		args     []string
+		code     int
+		requests int
+	}{
+		{"cold cache", []string{"check", "nodejs", "22"}, 4, 1},
+		{"warm cache", []string{"check", "nodejs", "22"}, 0, 0},
+		{"warm cache, EOL version", []string{"check", "nodejs", "18"}, 1, 0},
+		{"refresh", []string{"--refresh", "check", "nodejs", "22"}, 4, 1},
+		{"cold cache, another product", []string{"check", "python", "3.12"}, 4, 1},
+	}
+	for _, step := range steps {
+		before := api.count("nodejs") + api.count("python")
+		args := append([]string{"--api-url", api.apiURL(), "--no-network-on-cache-hit"}, step.args...)
+		got := run(t, env, args...)
+		if got.code != step.code {
+			t.Errorf("%s: exit code = %d, want %d\nstderr: %s", step.name, got.code, step.code, got.stderr)
+		}
+		if n := api.count("nodejs") + api.count("python") - before; n != step.requests {
+			t.Errorf("%s: %d request(s), want %d", step.name, n, step.requests)
+		}
+		if step.code == exitNetworkUsed && !strings.Contains(got.stderr, "network request(s) were made") {
+			t.Errorf("%s: stderr = %q, want it to say requests were made", step.name, got.stderr)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var outputFormat string
+var outputFile string
+var mergeDuplicateProducts bool
+var noHeader bool
+
+// formatters render the results of a bulk check in one of the --format
+// formats.
+var formatters = map[string]func(w io.Writer, report Report) error{
+	"text":        writeText,
+	"grouped":     writeGrouped,
+	"table":       writeTable,
+	"csv":         writeCSV,
+	"env":         writeEnv,
+	"json":        writeJSON,
+	"openmetrics": writeOpenMetrics,
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addJSONFlags adds --json, which prints what as JSON, and
+// --include-provenance to a command. Commands with --format should add it
+// first, since --json is short for one of its formats.
+func addJSONFlags(cmd *cobra.Command, what string) {
+	usage := "Print " + what + " as JSON"
+	if cmd.Flags().Lookup("format") != nil {
+		usage += " (same as --format json)"
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, usage)
+	cmd.Flags().BoolVar(&includeProvenance, "include-provenance", false, "With --json, include where the data behind each result came from (URL, status, fetch time, cache)")
+}
+
+// Report is what bulk commands render: the results along with the verdict of
+// the run, so JSON consumers don't have to interpret our exit codes.
+type Report struct {
+	OK       bool `json:"ok"`
+	ExitCode int  `json:"exitCode"`
+	// Incomplete is set when the run was cancelled before every item was
+	// checked, in which case Results only holds the finished ones.
+	Incomplete bool     `json:"incomplete,omitempty"`
+	Results    []Result `json:"results"`
+}
+
+// newReport builds the report for a run that ends by returning verdict.
+func newReport(results []Result, verdict error) Report {
+	if results == nil {
+		results = []Result{}
+	}
+	code := exitCode(verdict)
+	return Report{
+		OK:         verdict == nil,
+		ExitCode:   code,
+		Incomplete: code == exitIncomplete,
+		Results:    results,
+	}
+}
+
+// writeReport renders a report in the selected format to stdout, or to
+// --output-file if one is given.
+func writeReport(report Report) error {
+	format := outputFormat
+	if jsonOutput {
+		format = "json"
+	}
+	if mergeDuplicateProducts && format == "text" {
+		format = "grouped"
+	}
+	formatter, ok := formatters[format]
+	if !ok {
+		return fmt.Errorf("Unknown format %q, expected one of %s", format, strings.Join(formatNames(), ", "))
+	}
+
+	if outputFile == "" {
+		return formatter(os.Stdout, report)
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("Error creating output file: %s", err)
+	}
+	if err := formatter(file, report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeText(w io.Writer, report Report) error {
+	for _, r := range report.Results {
+		if r.Error != "" {
+			fmt.Fprintf(w, "Error checking %s %s: %s%s\n", r.Product, r.Version, r.Error, snapshotNote(r))
+			continue
+		}
+		fmt.Fprintln(w, describeResult(r))
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, report Report) error {
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// writeTable renders the results as a table with one row per version. When
+// the results come from several files, a SOURCE column lists the files each
+// version was found in.
+func writeTable(w io.Writer, report Report) error {
+	withSource := false
+	for _, r := range report.Results {
+		withSource = withSource || r.Source != ""
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if !noHeader {
+		if withSource {
+			fmt.Fprint(tw, "SOURCE\t")
+		}
+		fmt.Fprintln(tw, "PRODUCT\tVERSION\tSTATUS\tEOL\tSUPPORT")
+	}
+	for _, r := range report.Results {
+		if withSource {
+			fmt.Fprintf(tw, "%s\t", r.Source)
+		}
+		version := r.Version
+		if version == "" {
+			version = "-"
+		}
+		if r.Error != "" {
+			fmt.Fprintf(tw, "%s\t%s\terror\t%s\t\n", r.Product, version, r.Error+snapshotNote(r))
+			continue
+		}
+		eol, support := r.EOL, r.Support
+		if eol == "" {
+			eol = "-"
+		}
+		if support == "" {
+			support = "-"
+		}
+		support += snapshotNote(r)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Product, version, r.Status, eol, support)
+	}
+	return tw.Flush()
+}
+
+// writeCSV renders the results as CSV, leaving fields we don't know empty.
+func writeCSV(w io.Writer, report Report) error {
+	cw := csv.NewWriter(w)
+	if !noHeader {
+		cw.Write([]string{"source", "product", "version", "status", "eol", "support", "error"})
+	}
+	for _, r := range report.Results {
+		cw.Write([]string{r.Source, r.Product, r.Version, string(r.Status), r.EOL, r.Support, r.Error})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeGrouped lists the results under one header per product, with a
+// compact table of its versions, so inventories with many versions of the
+// same product stay readable.
+func writeGrouped(w io.Writer, report Report) error {
+	var products []string
+	byProduct := map[string][]Result{}
+	for _, r := range report.Results {
+		if _, ok := byProduct[r.Product]; !ok {
+			products = append(products, r.Product)
+		}
+		byProduct[r.Product] = append(byProduct[r.Product], r)
+	}
+
+	for i, product := range products {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		group := byProduct[product]
+		if len(group) == 1 {
+			fmt.Fprintf(w, "%s (1 version)\n", product)
+		} else {
+			fmt.Fprintf(w, "%s (%d versions)\n", product, len(group))
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  VERSION\tSTATUS\tEOL")
+		for _, r := range group {
+			if r.Error != "" {
+				fmt.Fprintf(tw, "  %s\terror\t%s\n", r.Version, r.Error+snapshotNote(r))
+				continue
+			}
+			eol := r.EOL
+			if eol == "" {
+				eol = "-"
+			}
+			eol += snapshotNote(r)
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", r.Version, r.Status, eol)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
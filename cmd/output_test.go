@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReportExitCode(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: nodejs\n  version: \"18\"\n")
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"EOL version", nil, 1},
+		{"network used on a cold cache", []string{"--no-network-on-cache-hit"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "check-inventory", "--json", inventory}, tt.args...)
+			got := run(t, nil, args...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			var report Report
+			if err := json.Unmarshal([]byte(got.stdout), &report); err != nil {
+				t.Fatalf("parsing report: %s\n%s", err, got.stdout)
+			}
+			if report.ExitCode != got.code || report.OK {
+				t.Errorf("report has exitCode %d, ok %t; the run exited with %d", report.ExitCode, report.OK, got.code)
+			}
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		verdict    error
+		ok         bool
+		code       int
+		incomplete bool
+	}{
+		{"passed", nil, true, 0, false},
+		{"failed", fmt.Errorf("2 version(s) are %w", errEOL), false, 1, false},
+		{"incomplete", &exitError{exitIncomplete, errors.New("deadline exceeded")}, false, 3, true},
+		{"warning", &exitError{78, errors.New("soon")}, false, 78, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := newReport(nil, tt.verdict)
+			if report.OK != tt.ok || report.ExitCode != tt.code || report.Incomplete != tt.incomplete {
+				t.Errorf("got %+v, want ok %t, exitCode %d, incomplete %t", report, tt.ok, tt.code, tt.incomplete)
+			}
+			if report.Results == nil {
+				t.Error("results are null in JSON")
+			}
+		})
+	}
+}
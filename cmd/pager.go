@@ -0,0 +1,56 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+
+	"golang.org/x/term"
+)
+
+var noPager bool
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// startPager returns the writer long output should go to. When stdout is a
+// terminal and --no-pager isn't set, that is the stdin of $PAGER (default
+// "less -R", so color codes pass through untouched); otherwise it is stdout
+// itself. The returned function flushes the output and waits for the pager
+// to exit, and has to be called once everything is written.
+func startPager() (io.Writer, func()) {
+	if noPager || !isTerminal(os.Stdout) {
+		return os.Stdout, func() {}
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less -R"
+	}
+	pagerCmd := exec.Command("sh", "-c", pager)
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Like git: quit if the output fits on one screen, keep raw control
+		// characters and don't clear the screen on exit.
+		pagerCmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	in, err := pagerCmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, func() {}
+	}
+	if err := pagerCmd.Start(); err != nil {
+		return os.Stdout, func() {}
+	}
+	return in, func() {
+		in.Close()
+		pagerCmd.Wait()
+	}
+}
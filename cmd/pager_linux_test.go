@@ -0,0 +1,51 @@
+//go:build linux
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	plain := run(t, nil, "--api-url", api.apiURL(), "list", "nodejs")
+	if plain.code != 0 || plain.stdout == "" {
+		t.Fatalf("list without a terminal: exit code %d, stdout %q\nstderr: %s", plain.code, plain.stdout, plain.stderr)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		tty   bool
+		paged bool
+	}{
+		{"terminal", []string{"list", "nodejs"}, true, true},
+		{"terminal, --no-pager", []string{"list", "nodejs", "--no-pager"}, true, false},
+		{"pipe", []string{"list", "nodejs"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paged := filepath.Join(t.TempDir(), "paged")
+			cmd := command(t, []string{"PAGER=cat > " + paged}, append([]string{"--api-url", api.apiURL()}, tt.args...)...)
+			if tt.tty {
+				_, tty := openPTY(t)
+				cmd.Stdout = tty
+			}
+			if err := cmd.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(paged)
+			switch {
+			case tt.paged && err != nil:
+				t.Fatalf("the pager didn't run: %s", err)
+			case tt.paged && string(data) != plain.stdout:
+				t.Errorf("paged output = %q, want %q", data, plain.stdout)
+			case !tt.paged && err == nil:
+				t.Errorf("the pager ran, with %q", data)
+			}
+		})
+	}
+}
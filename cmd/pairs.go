@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+// checkPairsCmd represents the check-pairs command
+var checkPairsCmd = &cobra.Command{
+	Use:   "check-pairs <name> <version> [<name> <version>...]",
+	Short: "Check several product versions given as name and version pairs",
+	Long: `Check several product versions at once, given as name and version pairs:
+
+  date-reaper check-pairs nodejs 18 python 3.9 go 1.20
+
+Each product's release cycles are fetched once, however many of its
+versions are listed.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || len(args)%2 != 0 {
+			return fmt.Errorf("Expected name and version pairs, got %d argument(s)", len(args))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		total := len(args) / 2
+
+		if plan {
+			var items []InventoryItem
+			for i := 0; i < len(args); i += 2 {
+				items = append(items, InventoryItem{Product: args[i], Version: args[i+1]})
+			}
+			showPlan(items)
+			return nil
+		}
+
+		var products []string
+		versionsOf := map[string][]string{}
+		for i := 0; i < len(args); i += 2 {
+			name := args[i]
+			if _, ok := versionsOf[name]; !ok {
+				products = append(products, name)
+			}
+			versionsOf[name] = append(versionsOf[name], args[i+1])
+		}
+
+		var results []Result
+		var incomplete error
+		for _, name := range products {
+			cycles, provenance, err := fetchCycles(ctx, name)
+			if err != nil && ctx.Err() != nil {
+				incomplete = incompleteError(ctx, len(results), total)
+				break
+			}
+			for _, version := range versionsOf[name] {
+				if err != nil {
+					results = append(results, errorResult(name, version, provenance, err))
+					continue
+				}
+				v, ok := matchCycle(cycles, version)
+				if !ok {
+					results = append(results, errorResult(name, version, provenance, endoflife.ErrVersionNotFound))
+					continue
+				}
+				results = append(results, newResult(name, version, v, provenance))
+			}
+		}
+
+		verdict := bulkVerdict(results, incomplete)
+		if err := writeReport(newReport(results, verdict)); err != nil {
+			return err
+		}
+		remediate(ctx, results, verdict)
+		return verdict
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkPairsCmd)
+
+	checkPairsCmd.Flags().StringSliceVar(&acceptStates, "accept", nil, "Only pass if every version is in one of these states: supported, maintenance, unknown, eol, soon")
+	checkPairsCmd.Flags().IntVar(&soonDays, "soon-days", 30, "Number of days before EOL a version is considered to be EOL soon")
+	checkPairsCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text, grouped, table, csv, env, json or openmetrics")
+	checkPairsCmd.Flags().BoolVar(&noHeader, "no-header", false, "Leave out the header row of table and csv output")
+	addMinRemainingFlag(checkPairsCmd)
+	addRemediationFlag(checkPairsCmd)
+	addJSONFlags(checkPairsCmd, "the report")
+	addPlanFlag(checkPairsCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckPairs(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	got := run(t, nil, "--api-url", api.apiURL(), "check-pairs", "nodejs", "18", "python", "3.9", "nodejs", "20", "nodejs", "16", "--format", "table")
+	want := strings.Join([]string{
+		"PRODUCT  VERSION  STATUS       EOL                SUPPORT",
+		"nodejs   18       eol          2025-04-30         2023-10-18",
+		"nodejs   20       maintenance  " + daysFromNow(2000) + "         2024-10-22",
+		"nodejs   16       error        Version not found  ",
+		"python   3.9      eol          2025-10-31         2022-05-17",
+	}, "\n") + "\n"
+	if got.code != 1 || got.stdout != want {
+		t.Errorf("exit code %d, stdout =\n%s\nwant\n%s\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+	for _, product := range []string{"nodejs", "python"} {
+		if n := api.count(product); n != 1 {
+			t.Errorf("%s was fetched %d times, want once", product, n)
+		}
+	}
+
+	for _, args := range [][]string{{"nodejs"}, {"nodejs", "18", "python"}} {
+		got := run(t, nil, append([]string{"--api-url", api.apiURL(), "check-pairs"}, args...)...)
+		want := fmt.Sprintf("Error: Expected name and version pairs, got %d argument(s)\n", len(args))
+		if got.code != 1 || !strings.HasPrefix(got.stderr, want) {
+			t.Errorf("%v: exit code %d, stderr %q, want it to start with %q", args, got.code, got.stderr, want)
+		}
+	}
+}
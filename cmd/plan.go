@@ -0,0 +1,91 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// plan makes the bulk commands print what they would check, and where each
+// product's data would come from, instead of checking anything.
+var plan bool
+
+// PlannedProduct is one product a run would look up, with the distinct
+// versions it would check.
+type PlannedProduct struct {
+	Product  string
+	Versions []string
+	Source   string
+}
+
+// plannedSource says where fetchProduct would get a product's data from,
+// following the same rules without fetching anything.
+func plannedSource(name string) string {
+	if dataGitDir != "" {
+		return "git"
+	}
+	if !cacheReadable() {
+		return "network"
+	}
+	_, storedAt, err := readCache(name)
+	switch {
+	case err != nil:
+		return "network"
+	case time.Since(storedAt) < cacheTTL && !refreshCache:
+		return "cache"
+	default:
+		return "network, stale cache as fallback"
+	}
+}
+
+// makePlan lists the distinct products and versions of items, in the order
+// they first appear.
+func makePlan(items []InventoryItem) []PlannedProduct {
+	var planned []PlannedProduct
+	index := map[string]int{}
+	seen := map[InventoryItem]bool{}
+	for _, item := range items {
+		item.Source = ""
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		i, ok := index[item.Product]
+		if !ok {
+			i = len(planned)
+			index[item.Product] = i
+			planned = append(planned, PlannedProduct{Product: item.Product, Source: plannedSource(item.Product)})
+		}
+		planned[i].Versions = append(planned[i].Versions, item.Version)
+	}
+	return planned
+}
+
+// addPlanFlag adds --plan to a command that checks a list of versions.
+func addPlanFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&plan, "plan", false, "Print the products and versions that would be checked, and whether their data would come from the cache or the network, without checking them")
+}
+
+func printPlan(w io.Writer, planned []PlannedProduct) {
+	versions := 0
+	for _, p := range planned {
+		versions += len(p.Versions)
+	}
+	fmt.Fprintf(w, "Would check %d version(s) of %d product(s):\n", versions, len(planned))
+	for _, p := range planned {
+		fmt.Fprintf(w, "  %s %s (%s)\n", p.Product, strings.Join(p.Versions, ", "), p.Source)
+	}
+}
+
+// showPlan prints the plan for items to stdout.
+func showPlan(items []InventoryItem) {
+	printPlan(os.Stdout, makePlan(items))
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	cache := t.TempDir()
+	for _, name := range []string{"nodejs", "python"} {
+		if err := os.WriteFile(filepath.Join(cache, name+".json"), []byte(testProducts()[name]), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-2 * cacheTTL)
+	if err := os.Chtimes(filepath.Join(cache, "python.json"), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	a := writeFile(t, "a.yaml", `- product: nodejs
+  version: "22"
+- product: python
+  version: "3.12"
+- product: nodejs
+  version: "18"
+`)
+	b := writeFile(t, "b.yaml", `- product: go
+  version: "1.22"
+- product: nodejs
+  version: "22"
+- product: python
+  version: "3.9"
+`)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"cache state", nil, []string{
+			"Would check 5 version(s) of 3 product(s):",
+			"  nodejs 22, 18 (cache)",
+			"  python 3.12, 3.9 (network, stale cache as fallback)",
+			"  go 1.22 (network)",
+		}},
+		{"--refresh", []string{"--refresh"}, []string{
+			"Would check 5 version(s) of 3 product(s):",
+			"  nodejs 22, 18 (network, stale cache as fallback)",
+			"  python 3.12, 3.9 (network, stale cache as fallback)",
+			"  go 1.22 (network)",
+		}},
+		{"--no-cache", []string{"--no-cache"}, []string{
+			"Would check 5 version(s) of 3 product(s):",
+			"  nodejs 22, 18 (network)",
+			"  python 3.12, 3.9 (network)",
+			"  go 1.22 (network)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			args := append([]string{"--api-url", api.apiURL(), "check-inventory", "--plan", a, b}, tt.args...)
+			got := run(t, []string{"DATE_REAPER_CACHE_DIR=" + cache}, args...)
+			if want := strings.Join(tt.want, "\n") + "\n"; got.code != 0 || got.stdout != want {
+				t.Errorf("exit code %d, stdout =\n%s\nwant\n%s\nstderr: %s", got.code, got.stdout, want, got.stderr)
+			}
+			for _, product := range []string{"nodejs", "python", "go"} {
+				if n := api.count(product); n != 0 {
+					t.Errorf("%s was fetched %d times, want nothing fetched", product, n)
+				}
+			}
+		})
+	}
+}
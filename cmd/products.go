@@ -0,0 +1,139 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+// fetchProducts returns the names of all products endoflife.date tracks.
+func fetchProducts(ctx context.Context) ([]string, error) {
+	body, _, err := fetchProduct(ctx, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	var products []string
+	if err := json.Unmarshal(body, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// validateProduct makes sure endoflife.date knows a product, suggesting
+// similarly named ones if it doesn't.
+func validateProduct(ctx context.Context, name string) error {
+	_, provenance, err := fetchProduct(ctx, name)
+	if err == nil {
+		return nil
+	}
+	if provenance.Status != http.StatusNotFound {
+		return fmt.Errorf("Error fetching %s: %s", name, err)
+	}
+
+	products, err := fetchProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("Unknown product %q", name)
+	}
+	if suggestions := suggest(name, products); len(suggestions) > 0 {
+		return fmt.Errorf("Unknown product %q, did you mean %s?", name, strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("Unknown product %q, run `date-reaper products` to see all of them", name)
+}
+
+var withLatest bool
+var limitProducts int
+
+// scanConfirmThreshold is how many products --with-latest may query before
+// asking for confirmation.
+const scanConfirmThreshold = 25
+
+// guardScan makes sure querying count products is intended: it needs --yes,
+// or a confirmation when running interactively.
+func guardScan(count int) error {
+	if count <= scanConfirmThreshold || assumeYes {
+		return nil
+	}
+	question := fmt.Sprintf("This queries the API for %d products. Continue?", count)
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("Refusing to query %d products without --yes (or use --limit-products)", count)
+	}
+	if !confirm(os.Stdin, question) {
+		return errors.New("Aborted")
+	}
+	return nil
+}
+
+// productsCmd represents the products command
+var productsCmd = &cobra.Command{
+	Use:   "products",
+	Short: "List all products endoflife.date tracks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		products, err := fetchProducts(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if limitProducts > 0 && limitProducts < len(products) {
+			products = products[:limitProducts]
+		}
+		if !withLatest {
+			out, done := startPager()
+			defer done()
+
+			for _, product := range products {
+				fmt.Fprintln(out, product)
+			}
+			return nil
+		}
+
+		if err := guardScan(len(products)); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		out, done := startPager()
+		defer done()
+
+		now := today()
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PRODUCT\tLATEST\tRELEASED\tSTATUS")
+		for i, product := range products {
+			versions, _, err := fetchCycles(ctx, product)
+			if err != nil {
+				if ctx.Err() != nil {
+					w.Flush()
+					return incompleteError(ctx, i, len(products))
+				}
+				fmt.Fprintf(w, "%s\t-\t-\terror: %s\n", product, err)
+				continue
+			}
+			if latest := mostRecent(versions, 1); len(latest) > 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", product, latest[0].Cycle, latest[0].ReleaseDate, endoflife.CycleStatus(latest[0], now))
+			}
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(productsCmd)
+
+	productsCmd.Flags().BoolVar(&withLatest, "with-latest", false, "Look up the newest cycle of every product (one request per product)")
+	productsCmd.Flags().IntVar(&limitProducts, "limit-products", 0, "Only consider the first N products")
+	productsCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask for confirmation before querying many products")
+	productsCmd.Flags().BoolVar(&noPager, "no-pager", false, "Don't pipe the output through $PAGER")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProductsScanGuard(t *testing.T) {
+	products := map[string]string{}
+	var names []string
+	for i := 0; i < 30; i++ {
+		name := fmt.Sprintf("p%02d", i)
+		names = append(names, `"`+name+`"`)
+		products[name] = `[{"cycle":"1","releaseDate":"2024-01-01","eol":false,"latest":"1.0.0"}]`
+	}
+	products["all"] = "[" + strings.Join(names, ",") + "]"
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		rows     int
+		stderr   string
+		requests int
+	}{
+		{"refused without --yes", nil, 1, 0, "Error: Refusing to query 30 products without --yes (or use --limit-products)\n", 0},
+		{"--yes", []string{"--yes"}, 0, 30, "", 30},
+		{"--limit-products under the threshold", []string{"--limit-products", "25"}, 0, 25, "", 25},
+		{"--limit-products over the threshold", []string{"--limit-products", "26"}, 1, 0, "Error: Refusing to query 26 products without --yes (or use --limit-products)\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, products)
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "products", "--with-latest"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+			if tt.rows > 0 {
+				lines := strings.Split(strings.TrimSuffix(got.stdout, "\n"), "\n")
+				if len(lines) != tt.rows+1 || !strings.HasPrefix(lines[0], "PRODUCT") {
+					t.Errorf("stdout = %q, want a header and %d rows", got.stdout, tt.rows)
+				} else if fields := strings.Fields(lines[1]); !reflect.DeepEqual(fields, []string{"p00", "1", "2024-01-01", "supported"}) {
+					t.Errorf("first row = %q", lines[1])
+				}
+			}
+			requests := 0
+			for i := 0; i < 30; i++ {
+				requests += api.count(fmt.Sprintf("p%02d", i))
+			}
+			if requests != tt.requests {
+				t.Errorf("%d product requests, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
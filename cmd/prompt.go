@@ -0,0 +1,46 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var assumeYes bool
+var interactive bool
+
+// errEOL is what a check fails with when it finds EOL versions, so
+// --interactive can tell that apart from other failures.
+var errEOL = errors.New("EOL")
+
+// confirm asks a yes/no question on stderr and reads the answer from in,
+// defaulting to no.
+func confirm(in io.Reader, question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// proceedAnyway asks, with --interactive, whether a run that found EOL
+// versions should pass anyway, as a local pre-push hook might want. It never
+// asks when in isn't a terminal, so CI behaves as without the flag.
+func proceedAnyway(in *os.File, err error) bool {
+	return askToProceed(in, isTerminal(in), err)
+}
+
+// askToProceed is proceedAnyway for any reader, told whether it is a
+// terminal.
+func askToProceed(in io.Reader, terminal bool, err error) bool {
+	if !interactive || !errors.Is(err, errEOL) || !terminal {
+		return false
+	}
+	return confirm(in, "Proceed anyway?")
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a pseudo-terminal, so a run's stdin can be a terminal in
+// tests.
+func openPTY(t *testing.T) (master *os.File, tty *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %s", err)
+	}
+	t.Cleanup(func() { master.Close() })
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatal(err)
+	}
+	n, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tty, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tty.Close() })
+	return master, tty
+}
+
+func TestInteractivePromptAfterResults(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: nodejs\n  version: \"18\"\n")
+
+	tests := []struct {
+		name   string
+		args   []string
+		answer string
+		code   int
+		result string
+		err    string
+	}{
+		{"check, yes", []string{"check", "nodejs", "18"}, "y\n", 0, "Nodejs 18 is EOL since 2025-04-30", "Error: EOL"},
+		{"check, no", []string{"check", "nodejs", "18"}, "n\n", 1, "Nodejs 18 is EOL since 2025-04-30", "Error: EOL"},
+		{"inventory, yes", []string{"check-inventory", inventory}, "yes\n", 0, "Nodejs 18 is EOL since 2025-04-30", "Error: 1 version(s) are EOL"},
+		{"inventory json, no", []string{"check-inventory", "--json", inventory}, "\n", 1, `"exitCode": 1`, "Error: 1 version(s) are EOL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			master, tty := openPTY(t)
+			if _, err := master.WriteString(tt.answer); err != nil {
+				t.Fatal(err)
+			}
+			cmd := command(t, nil, append([]string{"--api-url", api.apiURL(), "--interactive"}, tt.args...)...)
+			var output bytes.Buffer
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, &output, &output
+
+			err := cmd.Run()
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d\noutput: %s", code, tt.code, output.String())
+			}
+
+			// What failed comes first, then the question.
+			out := output.String()
+			result, failure, question := strings.Index(out, tt.result), strings.Index(out, tt.err), strings.Index(out, "Proceed anyway? [y/N]")
+			if result < 0 || failure < 0 || question < 0 || !(result < failure && failure < question) {
+				t.Errorf("want the result, then the error, then the question; got:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAskToProceed(t *testing.T) {
+	savedInteractive := interactive
+	t.Cleanup(func() { interactive = savedInteractive })
+
+	eol := fmt.Errorf("2 version(s) are %w", errEOL)
+	tests := []struct {
+		name        string
+		interactive bool
+		terminal    bool
+		err         error
+		stdin       string
+		want        bool
+	}{
+		{"yes", true, true, eol, "y\n", true},
+		{"full yes", true, true, eol, "Yes\n", true},
+		{"no", true, true, eol, "n\n", false},
+		{"enter defaults to no", true, true, eol, "\n", false},
+		{"end of input", true, true, eol, "", false},
+		{"not a terminal", true, false, eol, "y\n", false},
+		{"without --interactive", false, true, eol, "y\n", false},
+		{"other failures", true, true, errors.New("Version not found"), "y\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interactive = tt.interactive
+			stdin := strings.NewReader(tt.stdin)
+			if got := askToProceed(stdin, tt.terminal, tt.err); got != tt.want {
+				t.Errorf("askToProceed = %t, want %t", got, tt.want)
+			}
+			if !tt.terminal && stdin.Len() != len(tt.stdin) {
+				t.Error("read an answer from stdin that isn't a terminal")
+			}
+		})
+	}
+}
+
+func TestInteractiveWithoutTerminal(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	got := runWithInput(t, nil, strings.NewReader("y\n"), "--api-url", api.apiURL(), "--interactive", "check", "nodejs", "18")
+	if got.code != 1 {
+		t.Errorf("exit code = %d, want 1 as without --interactive", got.code)
+	}
+	if strings.Contains(got.stderr, "Proceed anyway?") {
+		t.Errorf("asked although stdin isn't a terminal:\n%s", got.stderr)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+var chunkGroupBy string
+
+// eolQuarter names the calendar quarter a result reaches EOL in, like
+// "Q3 2025", along with a key that sorts quarters chronologically. Results
+// without an EOL date get a bucket of their own, sorted after the quarters,
+// except those already EOL, which come first.
+func eolQuarter(r Result) (string, string) {
+	if r.Error != "" {
+		return "zz-error", "Not checked"
+	}
+	date, err := parseDay(r.EOL)
+	if err != nil {
+		if r.IsEOL {
+			return "0000-eol", "EOL (no date)"
+		}
+		if r.Status == endoflife.StatusUnknown {
+			return "zy-unknown", "Unknown EOL"
+		}
+		return "zx-supported", "No EOL date yet"
+	}
+	quarter := (int(date.Month())-1)/3 + 1
+	return fmt.Sprintf("%d-%d", date.Year(), quarter), fmt.Sprintf("Q%d %d", quarter, date.Year())
+}
+
+// printEOLQuarters prints the results grouped by the quarter they reach EOL
+// in, earliest first.
+func printEOLQuarters(results []Result) {
+	names := map[string]string{}
+	groups := map[string][]string{}
+	for _, r := range results {
+		key, name := eolQuarter(r)
+		names[key] = name
+		label := r.Version
+		if r.Source != "" {
+			label = fmt.Sprintf("%s (%s)", r.Version, r.Source)
+		}
+		groups[key] = append(groups[key], label)
+	}
+
+	for _, key := range sortedKeys(groups) {
+		versions := groups[key]
+		fmt.Printf("%s (%d): %s\n", names[key], len(versions), strings.Join(versions, ", "))
+	}
+}
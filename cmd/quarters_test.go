@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+func TestEOLQuarter(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{"dated", Result{EOL: "2025-08-31", Status: endoflife.StatusEOL, IsEOL: true}, "Q3 2025"},
+		{"first day of a quarter", Result{EOL: "2026-04-01", Status: endoflife.StatusSupported}, "Q2 2026"},
+		{"EOL without a date", Result{Status: endoflife.StatusEOL, IsEOL: true}, "EOL (no date)"},
+		{"no EOL date yet", Result{Status: endoflife.StatusSupported}, "No EOL date yet"},
+		{"unknown", Result{Status: endoflife.StatusUnknown}, "Unknown EOL"},
+		{"error", Result{Error: "Version not found"}, "Not checked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := eolQuarter(tt.result); got != tt.want {
+				t.Errorf("eolQuarter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	undated, _ := eolQuarter(Result{Status: endoflife.StatusEOL, IsEOL: true})
+	dated, _ := eolQuarter(Result{EOL: "2020-01-01", Status: endoflife.StatusEOL, IsEOL: true})
+	if undated >= dated {
+		t.Errorf("EOL (no date) should sort before the quarters, got keys %q and %q", undated, dated)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// rawCmd represents the raw command
+var rawCmd = &cobra.Command{
+	Use:   "raw <name>",
+	Short: "Print the upstream JSON of a product as the API returned it",
+	Long: `Print the upstream JSON of a product as the API returned it, including the
+fields date-reaper doesn't use. With --out the response is saved byte for
+byte, e.g. to serve it from a mirror later.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		body, _, err := fetchProduct(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputFile != "" {
+			if err := os.WriteFile(outputFile, body, 0o644); err != nil {
+				return fmt.Errorf("Error writing output file: %s", err)
+			}
+			return nil
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, body, "", "  "); err != nil {
+			return fmt.Errorf("Error parsing API response: %s", err)
+		}
+		fmt.Println(pretty.String())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rawCmd)
+
+	rawCmd.Flags().StringVarP(&outputFile, "out", "o", "", "Save the response to a file as is instead of printing it")
+}
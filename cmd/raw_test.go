@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRaw(t *testing.T) {
+	products := testProducts()
+	// Fields date-reaper doesn't model and unusual spacing, which must
+	// survive as is.
+	products["deno"] = `[ {"cycle":"2","releaseDate":"2024-10-09","eol":false,"latest":"2.1.4","link":"https://deno.com/blog/v2.0","discontinued":null,
+  "extendedSupport":"2026-01-01"} ]`
+	api := newAPIServer(t, products)
+
+	t.Run("out", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "deno.json")
+		got := run(t, nil, "--api-url", api.apiURL(), "raw", "deno", "--out", path)
+		if got.code != 0 || got.stdout != "" {
+			t.Fatalf("exit code %d, stdout %q\nstderr: %s", got.code, got.stdout, got.stderr)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != products["deno"] {
+			t.Errorf("saved %q, want the response byte for byte: %q", data, products["deno"])
+		}
+	})
+
+	t.Run("pretty-printed", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "raw", "deno")
+		var want bytes.Buffer
+		if err := json.Indent(&want, []byte(products["deno"]), "", "  "); err != nil {
+			t.Fatal(err)
+		}
+		if got.code != 0 || got.stdout != want.String()+"\n" {
+			t.Errorf("exit code %d, stdout %q, want %q\nstderr: %s", got.code, got.stdout, want.String()+"\n", got.stderr)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// minRemaining is --min-remaining as given, e.g. "180d", and
+// minRemainingDuration what it parses to. A version with less time than that
+// left until EOL fails the run, even though it is still supported.
+var minRemaining string
+var minRemainingDuration time.Duration
+
+// addMinRemainingFlag registers --min-remaining on a command whose verdict
+// applies it.
+func addMinRemainingFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&minRemaining, "min-remaining", "", "Fail if a version has less than this left until EOL, e.g. 180d, 26w or 1y")
+}
+
+// humanDurationUnits are the units parseHumanDuration knows on top of Go's
+// own, which stop at hours.
+var humanDurationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// parseHumanDuration parses durations like "180d", "26w" or "1y", as well as
+// anything time.ParseDuration understands, like "72h".
+func parseHumanDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range humanDurationUnits {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(value)
+}
+
+// validateMinRemaining parses --min-remaining.
+func validateMinRemaining() error {
+	minRemainingDuration = 0
+	if minRemaining == "" {
+		return nil
+	}
+	d, err := parseHumanDuration(minRemaining)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("Invalid --min-remaining %q, expected a duration like 180d, 26w or 1y", minRemaining)
+	}
+	minRemainingDuration = d
+	return nil
+}
+
+// remainingTooShort reports whether an EOL date is closer than
+// --min-remaining. Versions without an EOL date never are. Like the other
+// day counts, it counts from the start of today, so a version with exactly
+// 180 days left passes --min-remaining 180d all day.
+func remainingTooShort(eol string) bool {
+	if minRemainingDuration == 0 {
+		return false
+	}
+	date, err := parseDay(eol)
+	if err != nil {
+		return false
+	}
+	start, _ := parseDay(today())
+	return date.Sub(start) < minRemainingDuration
+}
+
+// minRemainingFailure fails a bulk run if any version has less than
+// --min-remaining left until EOL.
+func minRemainingFailure(results []Result) error {
+	var short []string
+	for _, r := range results {
+		if r.Error == "" && remainingTooShort(r.EOL) {
+			short = append(short, fmt.Sprintf("%s %s (EOL %s)", r.Product, r.Version, r.EOL))
+		}
+	}
+	if len(short) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d version(s) have less than %s left until EOL: %s", len(short), minRemaining, strings.Join(short, ", "))
+}
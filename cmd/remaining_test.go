@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMinRemainingBoundary(t *testing.T) {
+	savedClock, savedMinRemaining := clock, minRemaining
+	t.Cleanup(func() {
+		clock, minRemaining = savedClock, savedMinRemaining
+		validateMinRemaining()
+	})
+	// Late in the day, so counting from the current time instead of the
+	// start of the day would cut the last day short.
+	clock = func() time.Time { return time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		minRemaining string
+		eol          string
+		tooShort     bool
+	}{
+		{"180d", "2025-06-30", false}, // exactly 180 days left
+		{"180d", "2025-06-29", true},  // one day less
+		{"180d", "2025-07-01", false},
+		{"26w", "2025-07-02", false}, // exactly 182 days left
+		{"26w", "2025-07-01", true},
+		{"1y", "2026-01-01", false}, // exactly 365 days left
+		{"1y", "2025-12-31", true},
+		{"180d", "2024-12-31", true}, // already EOL
+		{"", "2025-01-02", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.minRemaining+" "+tt.eol, func(t *testing.T) {
+			minRemaining = tt.minRemaining
+			if err := validateMinRemaining(); err != nil {
+				t.Fatal(err)
+			}
+			if got := remainingTooShort(tt.eol); got != tt.tooShort {
+				t.Errorf("remainingTooShort(%s) with --min-remaining %q = %t, want %t", tt.eol, tt.minRemaining, got, tt.tooShort)
+			}
+		})
+	}
+}
+
+func TestMinRemainingOnlyOnCommandsThatApplyIt(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	eol := daysFromNow(100)
+
+	// nodejs 22 has about 100 days left.
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"exactly enough left", []string{"check", "nodejs", "22", "--min-remaining", "100d"}, 0, ""},
+		{"one day short", []string{"check", "nodejs", "22", "--min-remaining", "101d"}, 1, "Nodejs 22 has less than 101d left until EOL on " + eol},
+		{"bulk, one day short", []string{"check-pairs", "nodejs", "22", "python", "3.12", "--min-remaining", "101d"}, 1, "1 version(s) have less than 101d left until EOL: nodejs 22 (EOL " + eol + ")"},
+		{"not on check-k8s", []string{"check-k8s", "--version", "1.27", "--min-remaining", "1y"}, 1, "unknown flag: --min-remaining"},
+		{"not on check-chunk", []string{"check-chunk", "chunk.yaml", "--tool", "nodejs", "--min-remaining", "1y"}, 1, "unknown flag: --min-remaining"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL()}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr != "" && !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+)
+
+// remediation turns the EOL findings of a failed run into a checklist of
+// upgrades, printed to stderr so it stays out of machine-readable output.
+var remediation bool
+
+// addRemediationFlag registers --remediation on a command that prints the
+// checklist.
+func addRemediationFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&remediation, "remediation", false, "After a failed run, print a checklist of upgrades for the EOL versions")
+}
+
+// upgradeTarget suggests the cycle to upgrade to among those that aren't EOL.
+// A cycle that would be flagged again right away, reaching EOL within
+// --soon-days or with less than --min-remaining left, ranks last. Then LTS
+// cycles win, then ones still in active support, then the one with the most
+// time left until EOL (no EOL date counting as the most), and finally the
+// newest release.
+func upgradeTarget(versions []SoftwareVersion, now string) (SoftwareVersion, bool) {
+	start, _ := parseDay(now)
+	// left is how long a cycle has until EOL, and false without an EOL date.
+	left := func(v SoftwareVersion) (time.Duration, bool) {
+		eol, err := parseDay(endoflife.EOLDate(v))
+		if err != nil {
+			return 0, false
+		}
+		return eol.Sub(start), true
+	}
+	rank := func(v SoftwareVersion) int {
+		r := 0
+		if d, ok := left(v); !ok || (d > time.Duration(soonDays)*24*time.Hour && d >= minRemainingDuration) {
+			r += 4
+		}
+		if isLTS(v, now) {
+			r += 2
+		}
+		if endoflife.CycleStatus(v, now) == endoflife.StatusSupported {
+			r++
+		}
+		return r
+	}
+	// lastsLonger reports whether a has more time left until EOL than b.
+	lastsLonger := func(a SoftwareVersion, b SoftwareVersion) bool {
+		da, aok := left(a)
+		db, bok := left(b)
+		if aok && bok {
+			return da > db
+		}
+		return !aok && bok
+	}
+	better := func(a SoftwareVersion, b SoftwareVersion) bool {
+		if rank(a) != rank(b) {
+			return rank(a) > rank(b)
+		}
+		if lastsLonger(a, b) || lastsLonger(b, a) {
+			return lastsLonger(a, b)
+		}
+		return a.ReleaseDate > b.ReleaseDate
+	}
+
+	var target SoftwareVersion
+	found := false
+	for _, v := range versions {
+		if status := endoflife.CycleStatus(v, now); status != endoflife.StatusSupported && status != endoflife.StatusMaintenance {
+			continue
+		}
+		if !found || better(v, target) {
+			target, found = v, true
+		}
+	}
+	return target, found
+}
+
+// remediationStep renders the checklist line for one EOL result.
+func remediationStep(ctx context.Context, r Result) string {
+	versions, _, err := fetchCycles(ctx, r.Product)
+	if err != nil {
+		return fmt.Sprintf("Bump %s from %s to a supported version (no suggestion: %s)", r.Product, r.Version, err)
+	}
+	now := today()
+	target, ok := upgradeTarget(versions, now)
+	if !ok {
+		return fmt.Sprintf("Replace %s %s, no %s release is supported anymore", r.Product, r.Version, r.Product)
+	}
+
+	to := target.Cycle
+	if target.Latest != "" {
+		to = target.Latest
+	}
+	details := ""
+	if isLTS(target, now) {
+		details = "LTS, "
+	}
+	if eol := endoflife.EOLDate(target); eol != "" {
+		details += "EOL " + eol
+	} else {
+		details += "no EOL date yet"
+	}
+	step := fmt.Sprintf("Bump %s from %s to %s (%s)", r.Product, r.Version, to, details)
+	if r.Source != "" {
+		step += " in " + r.Source
+	}
+	return step
+}
+
+// printRemediation prints a checklist item per EOL result of a failed run.
+func printRemediation(ctx context.Context, w io.Writer, results []Result, verdict error) {
+	if !remediation || verdict == nil {
+		return
+	}
+	var steps []string
+	for _, r := range results {
+		if r.Error == "" && r.IsEOL {
+			steps = append(steps, remediationStep(ctx, r))
+		}
+	}
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Remediation:")
+	for _, step := range steps {
+		fmt.Fprintf(w, "- [ ] %s\n", step)
+	}
+}
+
+// remediate prints the remediation checklist of a run to stderr.
+func remediate(ctx context.Context, results []Result, verdict error) {
+	printRemediation(ctx, os.Stderr, results, verdict)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpgradeTarget(t *testing.T) {
+	savedSoonDays, savedMinRemaining := soonDays, minRemainingDuration
+	t.Cleanup(func() { soonDays, minRemainingDuration = savedSoonDays, savedMinRemaining })
+	soonDays = 30
+
+	const now = "2025-01-01"
+	tests := []struct {
+		name         string
+		versions     []SoftwareVersion
+		minRemaining time.Duration
+		want         string
+	}{
+		{"LTS over a newer release", []SoftwareVersion{
+			{Cycle: "23", ReleaseDate: "2024-10-16", LTS: false, EOL: "2025-06-01"},
+			{Cycle: "22", ReleaseDate: "2024-04-24", LTS: "2024-10-29", EOL: "2027-04-30"},
+		}, 0, "22"},
+		{"active support over maintenance", []SoftwareVersion{
+			{Cycle: "22", ReleaseDate: "2024-04-24", LTS: true, Support: "2025-10-21", EOL: "2027-04-30"},
+			{Cycle: "20", ReleaseDate: "2023-04-18", LTS: true, Support: "2024-10-22", EOL: "2026-04-30"},
+		}, 0, "22"},
+		{"more time left over a newer release", []SoftwareVersion{
+			{Cycle: "2.1", ReleaseDate: "2024-06-01", EOL: "2025-12-31"},
+			{Cycle: "2.0", ReleaseDate: "2024-01-01", EOL: "2027-01-01"},
+		}, 0, "2.0"},
+		{"no EOL date lasts longest", []SoftwareVersion{
+			{Cycle: "2", ReleaseDate: "2024-06-01", EOL: "2030-01-01"},
+			{Cycle: "1", ReleaseDate: "2020-01-01", EOL: false},
+		}, 0, "1"},
+		{"same EOL, newest release", []SoftwareVersion{
+			{Cycle: "3.1", ReleaseDate: "2024-01-01", EOL: "2026-01-01"},
+			{Cycle: "3.2", ReleaseDate: "2024-06-01", EOL: "2026-01-01"},
+		}, 0, "3.2"},
+		{"LTS reaching EOL soon ranks last", []SoftwareVersion{
+			{Cycle: "22", ReleaseDate: "2024-04-24", LTS: true, EOL: "2025-01-20"},
+			{Cycle: "23", ReleaseDate: "2024-10-16", LTS: false, Support: "2024-12-01", EOL: "2025-06-01"},
+		}, 0, "23"},
+		{"less than --min-remaining ranks last", []SoftwareVersion{
+			{Cycle: "22", ReleaseDate: "2024-04-24", LTS: true, EOL: "2025-06-01"},
+			{Cycle: "21", ReleaseDate: "2023-10-17", LTS: false, EOL: "2026-06-01"},
+		}, 365 * 24 * time.Hour, "21"},
+		{"EOL cycles are skipped", []SoftwareVersion{
+			{Cycle: "18", ReleaseDate: "2022-04-19", LTS: true, EOL: "2024-04-30"},
+			{Cycle: "19", ReleaseDate: "2022-10-18", EOL: true},
+		}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minRemainingDuration = tt.minRemaining
+			v, ok := upgradeTarget(tt.versions, now)
+			if v.Cycle != tt.want || ok != (tt.want != "") {
+				t.Errorf("upgradeTarget = %q, %t, want %q", v.Cycle, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemediation(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"failed check", []string{"check", "nodejs", "18", "--remediation"}, 1, "Remediation:\n- [ ] Bump nodejs from 18 to 22.9.0 (LTS, EOL " + daysFromNow(100) + ")\n"},
+		{"nearest cycle too close for --min-remaining", []string{"check", "nodejs", "18", "--remediation", "--min-remaining", "1y"}, 1, "- [ ] Bump nodejs from 18 to 20.17.0 (LTS, EOL " + daysFromNow(2000) + ")\n"},
+		{"bulk run", []string{"check-pairs", "nodejs", "18", "python", "3.9", "--remediation"}, 1, "Remediation:\n- [ ] Bump nodejs from 18 to 22.9.0 (LTS, EOL " + daysFromNow(100) + ")\n- [ ] Bump python from 3.9 to 3.12.7 (EOL " + daysFromNow(1500) + ")\n"},
+		{"passed check", []string{"check", "nodejs", "22", "--remediation"}, 0, ""},
+		{"not on check-k8s", []string{"check-k8s", "--version", "1.27", "--remediation"}, 1, "unknown flag: --remediation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL()}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr == "" && strings.Contains(got.stderr, "Remediation") {
+				t.Errorf("stderr = %q, want no remediation", got.stderr)
+			}
+			if !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pythonPackageProducts maps PyPI packages to endoflife.date products.
+var pythonPackageProducts = map[string]string{
+	"django":  "django",
+	"numpy":   "numpy",
+	"wagtail": "wagtail",
+}
+
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+
+// normalizePackageName applies the PEP 503 normalization, so Django and
+// django_foo match django and django-foo.
+func normalizePackageName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
+
+// pinnedVersion picks the concrete version out of a version specifier.
+// Exact (==, ===) and compatible release (~=) pins name a version we can
+// check; anything else only bounds a range.
+func pinnedVersion(spec string) (string, bool) {
+	spec = strings.TrimSpace(spec)
+	if strings.Contains(spec, ",") {
+		return "", false
+	}
+	for _, op := range []string{"===", "==", "~="} {
+		if version, ok := strings.CutPrefix(spec, op); ok {
+			return strings.TrimSuffix(strings.TrimSpace(version), ".*"), true
+		}
+	}
+	return "", false
+}
+
+// requirementsRefs extracts the recognized packages of a requirements.txt.
+func requirementsRefs(data []byte) ([]checkRef, error) {
+	var refs []checkRef
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line, _, _ = strings.Cut(line, ";")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		match := requirementPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		source := fmt.Sprintf("line %d", n)
+		product, ok := pythonPackageProducts[normalizePackageName(match[1])]
+		if !ok {
+			refs = append(refs, checkRef{Source: source, Skip: fmt.Sprintf("%s, it isn't tracked by endoflife.date", match[1])})
+			continue
+		}
+		version, ok := pinnedVersion(match[2])
+		if !ok {
+			refs = append(refs, checkRef{Source: source, Skip: fmt.Sprintf("%s, it only specifies a range, not a version", line)})
+			continue
+		}
+		refs = append(refs, checkRef{Source: source, Label: line, Product: product, Version: version})
+	}
+	return refs, scanner.Err()
+}
+
+// checkRequirementsCmd represents the check-requirements command
+var checkRequirementsCmd = &cobra.Command{
+	Use:         "check-requirements <path-to-requirements.txt>",
+	Annotations: map[string]string{inputsAnnotation: "requirements.txt"},
+	Short:       "Check the pinned frameworks of a Python requirements.txt for EOL versions",
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("Error reading requirements file: %s", err)
+		}
+		refs, err := requirementsRefs(data)
+		if err != nil {
+			return fmt.Errorf("Error parsing requirements file: %s", err)
+		}
+		return checkRefs(cmd.Context(), refs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkRequirementsCmd)
+
+	addMinRemainingFlag(checkRequirementsCmd)
+	addRemediationFlag(checkRequirementsCmd)
+	addJSONFlags(checkRequirementsCmd, "the report of the frameworks")
+	addPlanFlag(checkRequirementsCmd)
+}
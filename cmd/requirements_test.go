@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const requirementsFixture = `# web
+Django==4.2.11
+django_filter==23.5
+numpy~=1.26.0 ; python_version >= "3.9"
+wagtail[search]>=5.2
+flask>=2,<3
+Wagtail===6.0.*
+
+-r dev.txt
+`
+
+func TestRequirementsRefs(t *testing.T) {
+	got, err := requirementsRefs([]byte(requirementsFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []checkRef{
+		{Source: "line 2", Label: "Django==4.2.11", Product: "django", Version: "4.2.11"},
+		{Source: "line 3", Skip: "django_filter, it isn't tracked by endoflife.date"},
+		{Source: "line 4", Label: "numpy~=1.26.0", Product: "numpy", Version: "1.26.0"},
+		{Source: "line 5", Skip: "wagtail[search]>=5.2, it only specifies a range, not a version"},
+		{Source: "line 6", Skip: "flask, it isn't tracked by endoflife.date"},
+		{Source: "line 7", Label: "Wagtail===6.0.*", Product: "wagtail", Version: "6.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	products := testProducts()
+	products["django"] = `[
+{"cycle":"5.1","releaseDate":"2024-08-07","support":"` + daysFromNow(50) + `","eol":"` + daysFromNow(200) + `","latest":"5.1.2"},
+{"cycle":"4.2","releaseDate":"2023-04-03","lts":true,"support":"2023-12-04","eol":"` + daysFromNow(150) + `","latest":"4.2.16"},
+{"cycle":"3.2","releaseDate":"2021-04-06","lts":true,"support":"2021-12-07","eol":"2024-04-01","latest":"3.2.25"}
+]`
+	api := newAPIServer(t, products)
+	requirements := writeFile(t, "requirements.txt", "Django==3.2.25\nrequests==2.31.0\nnumpy>=1.26\n")
+
+	got := run(t, nil, "--api-url", api.apiURL(), "check-requirements", requirements)
+	want := strings.Join([]string{
+		"line 1: Django 3.2.25 is EOL since 2024-04-01",
+		"line 2: skipped requests, it isn't tracked by endoflife.date",
+		"line 3: skipped numpy>=1.26, it only specifies a range, not a version",
+	}, "\n") + "\n"
+	if got.code != 1 || got.stdout != want {
+		t.Errorf("exit code %d, stdout %q, want exit code 1 and stdout %q\nstderr: %s", got.code, got.stdout, want, got.stderr)
+	}
+}
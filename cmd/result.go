@@ -0,0 +1,117 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+var jsonOutput bool
+var includeProvenance bool
+
+// failedByEOL is the FailedBy of results failing just for being EOL, which
+// no flag is needed for.
+const failedByEOL = "eol"
+
+type Result = endoflife.Result
+
+func newResult(name string, version string, v SoftwareVersion, provenance Provenance) Result {
+	result := endoflife.NewResult(name, version, v, today())
+	result.Snapshot = snapshotDate(provenance)
+	if includeProvenance {
+		result.Provenance = &provenance
+	}
+	return result
+}
+
+func errorResult(name string, version string, provenance Provenance, err error) Result {
+	result := Result{
+		Product:  name,
+		Version:  version,
+		Error:    err.Error(),
+		Snapshot: snapshotDate(provenance),
+	}
+	if includeProvenance {
+		result.Provenance = &provenance
+	}
+	return result
+}
+
+type memoEntry struct {
+	result Result
+	err    error
+}
+
+var resultMemoMu sync.Mutex
+
+// resultMemo holds the results evaluated so far in this run, so an inventory
+// listing the same product version several times only evaluates it once. Its
+// keys carry every setting that changes a result, so tests and callers that
+// change one between evaluations don't get a stale result back.
+var resultMemo = map[string]memoEntry{}
+
+// evaluate checks a product version and builds its result, which carries any
+// lookup error too. The error is returned as well so callers can tell when
+// the run was cancelled; cancelled lookups aren't remembered.
+func evaluate(ctx context.Context, product string, version string) (Result, error) {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%t\x00%s\x00%s", product, version, today(), location, includeProvenance, matchGranularity, matchPick)
+	resultMemoMu.Lock()
+	entry, ok := resultMemo[key]
+	resultMemoMu.Unlock()
+	if ok {
+		return entry.result, entry.err
+	}
+
+	v, provenance, err := CheckVersion(ctx, product, version)
+	if err != nil && ctx.Err() != nil {
+		return Result{}, err
+	}
+	if err != nil {
+		entry = memoEntry{errorResult(product, version, provenance, err), err}
+	} else {
+		entry = memoEntry{newResult(product, version, v, provenance), nil}
+	}
+
+	resultMemoMu.Lock()
+	resultMemo[key] = entry
+	resultMemoMu.Unlock()
+	return entry.result, entry.err
+}
+
+// describeResult renders a one-line summary of a result.
+func describeResult(r Result) string {
+	return describeStatus(r) + snapshotNote(r)
+}
+
+func describeStatus(r Result) string {
+	eol := r.EOL
+	if eol == "" {
+		eol = "an unknown date"
+	}
+	switch r.Status {
+	case endoflife.StatusEOL:
+		return fmt.Sprintf("%s %s is EOL since %s", capitalize(r.Product), r.Version, eol)
+	case endoflife.StatusMaintenance:
+		return fmt.Sprintf("%s %s is in maintenance mode until %s", capitalize(r.Product), r.Version, eol)
+	case endoflife.StatusUnknown:
+		return fmt.Sprintf("%s %s has no known EOL date", capitalize(r.Product), r.Version)
+	default:
+		return fmt.Sprintf("%s %s is not EOL yet. It will be EOL on %s", capitalize(r.Product), r.Version, eol)
+	}
+}
+
+func printJSON(value interface{}) error {
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
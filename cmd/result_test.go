@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+func TestEvaluateMemoizes(t *testing.T) {
+	savedClient, savedMemo, savedProvenance := cliClient, resultMemo, includeProvenance
+	savedGranularity, savedPick, savedLocation := matchGranularity, matchPick, location
+	t.Cleanup(func() {
+		cliClient, resultMemo, includeProvenance = savedClient, savedMemo, savedProvenance
+		matchGranularity, matchPick, location = savedGranularity, savedPick, savedLocation
+	})
+
+	var fetches atomic.Int64
+	cliClient = endoflife.NewClient("", endoflife.Options{
+		Fetch: func(ctx context.Context, name string) ([]byte, Provenance, error) {
+			fetches.Add(1)
+			return []byte(testProducts()[name]), Provenance{}, nil
+		},
+		TTL: -1,
+	})
+
+	tests := []struct {
+		name       string
+		pairs      [][2]string
+		provenance bool
+		// change alters a setting after a first evaluation of nodejs 22,
+		// which then mustn't be reused.
+		change  func()
+		fetches int64
+	}{
+		{"one pair", [][2]string{{"nodejs", "22"}}, false, nil, 1},
+		{"duplicates", [][2]string{{"nodejs", "22"}, {"nodejs", "22"}, {"nodejs", "22"}}, false, nil, 1},
+		{"distinct versions", [][2]string{{"nodejs", "22"}, {"nodejs", "20"}, {"nodejs", "22"}, {"nodejs", "20"}}, false, nil, 2},
+		{"missing versions too", [][2]string{{"nodejs", "4"}, {"nodejs", "4"}}, false, nil, 1},
+		{"provenance is part of the key", [][2]string{{"nodejs", "22"}, {"nodejs", "22"}}, true, func() { includeProvenance = true }, 1},
+		{"granularity is part of the key", [][2]string{{"nodejs", "22"}, {"nodejs", "22"}}, false, func() { matchGranularity = "major" }, 1},
+		{"pick is part of the key", [][2]string{{"nodejs", "22"}, {"nodejs", "22"}}, false, func() { matchPick = "oldest" }, 1},
+		{"timezone is part of the key", [][2]string{{"nodejs", "22"}, {"nodejs", "22"}}, false, func() { location = time.FixedZone("UTC+0", 0) }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultMemo = map[string]memoEntry{}
+			includeProvenance = false
+			matchGranularity, matchPick, location = savedGranularity, savedPick, savedLocation
+			if tt.change != nil {
+				evaluate(context.Background(), "nodejs", "22")
+				tt.change()
+			}
+			fetches.Store(0)
+			for _, pair := range tt.pairs {
+				result, _ := evaluate(context.Background(), pair[0], pair[1])
+				if result.Product != pair[0] || result.Version != pair[1] {
+					t.Errorf("evaluate(%s, %s) = %+v", pair[0], pair[1], result)
+				}
+				if tt.provenance && result.Provenance == nil {
+					t.Errorf("evaluate(%s, %s) has no provenance", pair[0], pair[1])
+				}
+			}
+			if got := fetches.Load(); got != tt.fetches {
+				t.Errorf("evaluated %d time(s), want %d", got, tt.fetches)
+			}
+		})
+	}
+}
+
+func TestFailedBy(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	// A single check prints its result; bulk runs print a report, whose
+	// results are in the order given.
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		failedBy []string
+	}{
+		{"eol", []string{"check", "nodejs", "18"}, 1, []string{"eol"}},
+		{"min-remaining", []string{"check", "nodejs", "22", "--min-remaining", "1y"}, 1, []string{"--min-remaining"}},
+		{"fail-on-maintenance", []string{"check", "nodejs", "20", "--fail-on-maintenance"}, 1, []string{"--fail-on-maintenance"}},
+		{"error", []string{"check", "nodejs", "4", "-m"}, 1, []string{"--fail-on-missing"}},
+		{"strict", []string{"check", "nodejs", "22", "--strict", "--soon-days", "200"}, 1, []string{"--fail-on-soon (via --strict)"}},
+		{"accept", []string{"check", "nodejs", "20", "--accept", "supported"}, 1, []string{"--accept"}},
+		{"passed", []string{"check", "nodejs", "20"}, 0, []string{""}},
+		{"bulk eol", []string{"check-pairs", "nodejs", "18", "nodejs", "20"}, 1, []string{"eol", ""}},
+		{"bulk min-remaining", []string{"check-pairs", "nodejs", "22", "python", "3.12", "--min-remaining", "1y"}, 1, []string{"--min-remaining", ""}},
+		{"bulk accept", []string{"check-pairs", "nodejs", "22", "nodejs", "20", "--accept", "supported"}, 1, []string{"", "--accept"}},
+		{"bulk error", []string{"check-pairs", "nodejs", "4", "nodejs", "22"}, 0, []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append(append([]string{"--api-url", api.apiURL()}, tt.args...), "--json")...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			var report Report
+			if tt.args[0] == "check" {
+				report.Results = make([]Result, 1)
+				if err := json.Unmarshal([]byte(got.stdout), &report.Results[0]); err != nil {
+					t.Fatalf("parsing result: %s\n%s", err, got.stdout)
+				}
+			} else if err := json.Unmarshal([]byte(got.stdout), &report); err != nil {
+				t.Fatalf("parsing report: %s\n%s", err, got.stdout)
+			}
+			var failedBy []string
+			for _, r := range report.Results {
+				failedBy = append(failedBy, r.FailedBy)
+			}
+			if !slices.Equal(failedBy, tt.failedBy) {
+				t.Errorf("failedBy = %q, want %q\n%s", failedBy, tt.failedBy, got.stdout)
+			}
+		})
+	}
+}
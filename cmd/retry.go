@@ -0,0 +1,59 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var retries int
+var retryBudget int
+var retryOn []int
+
+// defaultRetryOn are the statuses retried unless --retry-on says otherwise:
+// rate limiting and the server errors that are usually temporary.
+var defaultRetryOn = []int{429, 500, 502, 503, 504}
+
+// retryBaseDelay is the wait before the first retry; it doubles after each
+// further attempt.
+var retryBaseDelay = 500 * time.Millisecond
+
+var retryMu sync.Mutex
+var retriesUsed int
+
+// takeRetry consumes one retry from the run-wide budget, reporting false once
+// it is exhausted. A negative budget means unlimited.
+func takeRetry() bool {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	if retryBudget >= 0 && retriesUsed >= retryBudget {
+		return false
+	}
+	retriesUsed++
+	return true
+}
+
+// validateRetryOn makes sure --retry-on only lists HTTP error statuses.
+func validateRetryOn() error {
+	for _, status := range retryOn {
+		if status < 400 || status > 599 {
+			return fmt.Errorf("Invalid --retry-on status %d, expected a 4xx or 5xx code", status)
+		}
+	}
+	return nil
+}
+
+// isTransient reports whether a failed request is worth retrying. Status 0
+// means the request never got a response, which is always retried.
+func isTransient(status int) bool {
+	return status == 0 || slices.Contains(retryOn, status)
+}
+
+func retryDelay(attempt int) time.Duration {
+	return retryBaseDelay << attempt
+}
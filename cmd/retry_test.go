@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers each path with its statuses in turn, repeating the
+// last one, and counts the requests for each path.
+type flakyServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func newFlakyServer(t *testing.T, statuses map[string][]int) *flakyServer {
+	t.Helper()
+	s := &flakyServer{requests: map[string]int{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		n := s.requests[r.URL.Path]
+		s.requests[r.URL.Path]++
+		s.mu.Unlock()
+		list := statuses[r.URL.Path]
+		status := list[min(n, len(list)-1)]
+		w.WriteHeader(status)
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *flakyServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// setRetries sets the retry flags for a test, without waiting between
+// retries, and puts them back afterwards.
+func setRetries(t *testing.T, n int, budget int, on []int) {
+	t.Helper()
+	savedRetries, savedBudget, savedOn, savedUsed, savedDelay := retries, retryBudget, retryOn, retriesUsed, retryBaseDelay
+	t.Cleanup(func() {
+		retries, retryBudget, retryOn, retriesUsed, retryBaseDelay = savedRetries, savedBudget, savedOn, savedUsed, savedDelay
+	})
+	retries, retryBudget, retryOn, retriesUsed, retryBaseDelay = n, budget, on, 0, time.Millisecond
+}
+
+func TestRetryBudget(t *testing.T) {
+	paths := []string{"/a.json", "/b.json", "/c.json"}
+	tests := []struct {
+		name   string
+		budget int
+		want   []int
+	}{
+		{"unlimited", -1, []int{3, 3, 3}},
+		{"none", 0, []int{1, 1, 1}},
+		{"used up partway", 3, []int{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRetries(t, 2, tt.budget, defaultRetryOn)
+			server := newFlakyServer(t, map[string][]int{"/a.json": {503}, "/b.json": {503}, "/c.json": {503}})
+			for _, path := range paths {
+				if _, _, err := fetchURL(context.Background(), server.URL+path); err == nil {
+					t.Errorf("%s: expected an error", path)
+				}
+			}
+			for i, path := range paths {
+				if got := server.count(path); got != tt.want[i] {
+					t.Errorf("%s: %d request(s), want %d", path, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRetryOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		on       []int
+		statuses []int
+		requests int
+		ok       bool
+	}{
+		{"listed by default", defaultRetryOn, []int{503}, 3, false},
+		{"not listed by default", defaultRetryOn, []int{404}, 1, false},
+		{"recovers", defaultRetryOn, []int{502, 200}, 2, true},
+		{"listed", []int{404}, []int{404}, 3, false},
+		{"no longer listed", []int{429}, []int{503}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRetries(t, 2, -1, tt.on)
+			server := newFlakyServer(t, map[string][]int{"/p.json": tt.statuses})
+			_, _, err := fetchURL(context.Background(), server.URL+"/p.json")
+			if (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %t", err, tt.ok)
+			}
+			if got := server.count("/p.json"); got != tt.requests {
+				t.Errorf("%d request(s), want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestValidateRetryOn(t *testing.T) {
+	tests := []struct {
+		on    []int
+		valid bool
+	}{
+		{defaultRetryOn, true},
+		{[]int{404, 599}, true},
+		{[]int{200}, false},
+		{[]int{503, 600}, false},
+	}
+	for _, tt := range tests {
+		setRetries(t, 2, -1, tt.on)
+		if err := validateRetryOn(); (err == nil) != tt.valid {
+			t.Errorf("validateRetryOn(%v) = %v, want valid %t", tt.on, err, tt.valid)
+		}
+	}
+}
@@ -4,7 +4,12 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -12,15 +17,166 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "date-reaper",
 	Short: "A utility for looking up EOL dates for software",
+	// A failed check isn't a usage mistake, so Execute prints the error on
+	// its own.
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The profile goes first, so its values are checked just like the
+		// ones given on the command line.
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+		if err := checkConflicts(cmd); err != nil {
+			return err
+		}
+		if err := validateFlags(); err != nil {
+			return err
+		}
+		startDeadline(cmd)
+		return nil
+	},
+}
+
+// validateFlags checks the flag values that need more than parsing, and
+// derives what the run needs from them, like the --tz location.
+func validateFlags() error {
+	validators := []func() error{
+		validateRetryOn,
+		validateAccept,
+		validateWarnExitCode,
+		validateMatchGranularity,
+		validateDataGit,
+		validateMinRemaining,
+		func() error {
+			_, err := extraHeaders()
+			return err
+		},
+		loadTimezone,
+	}
+	for _, validate := range validators {
+		if err := validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exit codes. Anything that makes a check fail exits with exitFailure.
+const (
+	exitFailure = 1
+	// exitIncomplete means the run stopped before every item was checked,
+	// because --deadline passed or it was interrupted.
+	exitIncomplete = 3
+	// exitNetworkUsed means API requests were made despite
+	// --no-network-on-cache-hit.
+	exitNetworkUsed = 4
+)
+
+// exitError makes Execute exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+var explainExitCode bool
+
+// explainExit renders the exit code a run ends with and why, e.g.
+// "exit 1 (failed): 3 version(s) are EOL".
+func explainExit(err error) string {
+	code := exitCode(err)
+	var meaning string
+	switch {
+	case code == 0:
+		return "exit 0 (passed)"
+	case code == exitIncomplete:
+		meaning = "incomplete"
+	case code == exitNetworkUsed:
+		meaning = "network used"
+	case code == warnExitCode:
+		meaning = "warning"
+	default:
+		meaning = "failed"
+	}
+	return fmt.Sprintf("exit %d (%s): %s", code, meaning, err)
+}
+
+// exitCode returns the code the process exits with when a command returns err.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// Ctrl-C cancels the run's context, so bulk commands can still report
+	// what they finished.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	verdict := rootCmd.ExecuteContext(ctx)
+	stopDeadline()
+	stop()
+	if verdict != nil {
+		fmt.Fprintln(os.Stderr, "Error:", verdict)
+	}
+	err := networkVerdict(verdict)
+	if err != nil && err != verdict {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	// Only asked now, after the results and what failed are out. A report
+	// already written still has the code the run failed with.
+	if proceedAnyway(os.Stdin, err) {
+		err = nil
+	}
+	if explainExitCode {
+		fmt.Fprintln(os.Stderr, explainExit(err))
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
-	//
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return fmt.Errorf("%w\nRun '%s --help' for usage.", err, cmd.CommandPath())
+	})
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default is $XDG_CONFIG_HOME/date-reaper/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Apply the named profile from the config file")
+	rootCmd.PersistentFlags().DurationVar(&deadline, "deadline", 0, "Stop the whole run after this long, reporting partial results (e.g. 30s)")
+	rootCmd.PersistentFlags().StringVar(&apiBaseURL, "api-url", apiBaseURL, "Base URL of the endoflife.date API or a mirror of it")
+	rootCmd.PersistentFlags().StringVar(&dataGitDir, "data-git-dir", "", "Read product data from api/<name>.json in this git clone instead of the API")
+	rootCmd.PersistentFlags().StringVar(&dataGitRef, "data-git-ref", "", "Git ref (e.g. a commit) of --data-git-dir to read product data at (default HEAD)")
+	rootCmd.PersistentFlags().StringVar(&unixSocket, "unix-socket", "", "Send API requests to this unix socket, e.g. of a sidecar mirror, using --api-url only for the path")
+	rootCmd.PersistentFlags().BoolVar(&noEmbedded, "no-embedded", false, "Don't fall back to the built-in snapshot of common products when the API and cache are unavailable")
+	rootCmd.PersistentFlags().BoolVar(&noNetworkOnCacheHit, "no-network-on-cache-hit", false, "Exit with code 4 if any API request was made, to verify the cache covered everything")
+	rootCmd.PersistentFlags().StringArrayVar(&apiHeaders, "api-header", nil, "Extra header to send to the API, e.g. 'Authorization: Bearer X' (repeatable, never logged)")
+	rootCmd.PersistentFlags().StringVar(&apiToken, "api-token", "", "Bearer token to send to the API (or set $DATE_REAPER_API_TOKEN, never logged)")
+	rootCmd.PersistentFlags().IntVar(&warnExitCode, "warn-exit-code", 0, "Exit with this code when nothing failed but versions reach EOL within --soon-days, e.g. 78 (0 keeps such runs passing)")
+	rootCmd.PersistentFlags().StringVar(&matchGranularity, "match-granularity", "", "Match versions to cycles by major, minor or exact version only (default: the longest cycle the version starts with)")
+	rootCmd.PersistentFlags().StringVar(&matchPick, "match-pick", "newest", "Cycle a version matches when the product only has cycles below it, like 18.0 and 18.1 for 18: newest or oldest")
+	rootCmd.PersistentFlags().StringVar(&timezone, "tz", "", "Timezone whose days EOL dates are compared in, e.g. America/New_York (default UTC)")
+	rootCmd.PersistentFlags().BoolVar(&interactive, "interactive", false, "When EOL versions are found and stdin is a terminal, ask whether to pass anyway")
+	rootCmd.PersistentFlags().BoolVar(&explainExitCode, "explain-exit-code", false, "Print the exit code and the reason for it to stderr at the end of the run")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Don't show progress on stderr")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log requests and cache use to stderr")
+	rootCmd.PersistentFlags().BoolVar(&trace, "trace", false, "Dump HTTP requests and responses (body truncated) to stderr")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 2, "Number of times to retry a request that failed with a network error or a --retry-on status")
+	rootCmd.PersistentFlags().IntSliceVar(&retryOn, "retry-on", defaultRetryOn, "HTTP statuses to retry, comma-separated")
+	rootCmd.PersistentFlags().IntVar(&retryBudget, "retry-budget", 20, "Maximum number of retries across the whole run (-1 for unlimited)")
+	rootCmd.PersistentFlags().StringVar(&cacheDirFlag, "cache-dir", "", "Directory to cache API responses in (or set $DATE_REAPER_CACHE_DIR, default is $XDG_CACHE_HOME/date-reaper)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Don't read cached API responses (fresh responses are still cached)")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Re-fetch cached API responses, falling back to the cache if the request fails")
+	rootCmd.PersistentFlags().BoolVar(&networkOnly, "network-only", false, "Ignore the cache completely for this run (no reads or writes)")
 }
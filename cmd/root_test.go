@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain lets tests run the CLI as a subprocess of the test binary itself,
+// so every run starts from fresh flags and its exit code can be checked.
+func TestMain(m *testing.M) {
+	if os.Getenv("DATE_REAPER_TEST_RUN") == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type runResult struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// run runs date-reaper with args, isolated from the user's config and cache.
+// env overrides the environment, e.g. to share a cache directory between
+// runs.
+func run(t *testing.T, env []string, args ...string) runResult {
+	t.Helper()
+	return runWithInput(t, env, nil, args...)
+}
+
+func runWithInput(t *testing.T, env []string, stdin io.Reader, args ...string) runResult {
+	t.Helper()
+	cmd := command(t, env, args...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	code := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("running %v: %s", args, err)
+	}
+	return runResult{stdout.String(), stderr.String(), code}
+}
+
+// command prepares a run of date-reaper with args, for tests that need to
+// interact with it while it runs.
+func command(t *testing.T, env []string, args ...string) *exec.Cmd {
+	t.Helper()
+	home := t.TempDir()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(),
+		"DATE_REAPER_TEST_RUN=1",
+		"HOME="+home,
+		"XDG_CONFIG_HOME="+filepath.Join(home, "config"),
+		"XDG_CACHE_HOME="+filepath.Join(home, "cache"),
+		"DATE_REAPER_CACHE_DIR="+filepath.Join(home, "cache", "date-reaper"),
+		"DATE_REAPER_API_TOKEN=",
+	)
+	cmd.Env = append(cmd.Env, env...)
+	return cmd
+}
+
+// apiServer serves product data like the endoflife.date API and counts the
+// requests for each product.
+type apiServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests map[string]int
+	delays   map[string]delay
+}
+
+type delay struct {
+	after    int
+	duration time.Duration
+}
+
+func newAPIServer(t *testing.T, products map[string]string) *apiServer {
+	t.Helper()
+	s := &apiServer{requests: map[string]int{}, delays: map[string]delay{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".json")
+		s.mu.Lock()
+		s.requests[name]++
+		var wait time.Duration
+		if d, ok := s.delays[name]; ok && s.requests[name] > d.after {
+			wait = d.duration
+		}
+		s.mu.Unlock()
+		select {
+		case <-time.After(wait):
+		case <-r.Context().Done():
+			return
+		}
+		body, ok := products[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// apiURL is the value to pass as --api-url.
+func (s *apiServer) apiURL() string {
+	return s.URL + "/"
+}
+
+// slow delays the responses for a product after its first few requests.
+func (s *apiServer) slow(name string, after int, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delays[name] = delay{after, duration}
+}
+
+func (s *apiServer) count(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[name]
+}
+
+// daysFromNow returns the date n days from today, for fixtures whose status
+// mustn't change as time passes.
+func daysFromNow(n int) string {
+	return time.Now().UTC().AddDate(0, 0, n).Format("2006-01-02")
+}
+
+// testProducts are API responses for tests: nodejs 22 is supported with
+// about 100 days left, 20 is in maintenance for years and 18 is EOL.
+func testProducts() map[string]string {
+	return map[string]string{
+		"nodejs": `[
+{"cycle":"22","releaseDate":"2024-04-24","lts":"2024-10-29","support":"` + daysFromNow(50) + `","eol":"` + daysFromNow(100) + `","latest":"22.9.0"},
+{"cycle":"20","releaseDate":"2023-04-18","lts":"2023-10-24","support":"2024-10-22","eol":"` + daysFromNow(2000) + `","latest":"20.17.0"},
+{"cycle":"18","releaseDate":"2022-04-19","lts":"2022-10-25","support":"2023-10-18","eol":"2025-04-30","latest":"18.20.4"}
+]`,
+		"python": `[
+{"cycle":"3.12","releaseDate":"2023-10-02","lts":false,"support":"2025-04-02","eol":"` + daysFromNow(1500) + `","latest":"3.12.7"},
+{"cycle":"3.9","releaseDate":"2020-10-05","lts":false,"support":"2022-05-17","eol":"2025-10-31","latest":"3.9.20"}
+]`,
+		"all": `["nodejs","python"]`,
+	}
+}
+
+// writeFile writes a fixture file into a fresh temporary directory.
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
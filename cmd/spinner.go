@@ -0,0 +1,58 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var quiet bool
+
+// spinnerDelay is how long a fetch may take before the spinner shows up, so
+// fast (or cached) checks never flash it.
+var spinnerDelay = 500 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// startSpinner shows a spinner with a message on stderr if the work it
+// stands for takes longer than spinnerDelay. It only does so when stderr is
+// a terminal and nothing else (--quiet, -v, --trace) wants stderr. The
+// returned function stops the spinner and clears its line, and has to be
+// called before printing anything.
+func startSpinner(message string) func() {
+	if quiet || verbose || trace || !isTerminal(os.Stderr) {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-stop:
+			return
+		case <-time.After(spinnerDelay):
+		}
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(os.Stderr, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], message)
+			select {
+			case <-stop:
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
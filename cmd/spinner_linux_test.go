@@ -0,0 +1,61 @@
+//go:build linux
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinner(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	// Slower than spinnerDelay, so a spinner on a terminal has to show.
+	api.slow("nodejs", 0, 800*time.Millisecond)
+
+	tests := []struct {
+		name    string
+		args    []string
+		tty     bool
+		spinner bool
+	}{
+		{"terminal", nil, true, true},
+		{"terminal, --quiet", []string{"--quiet"}, true, false},
+		{"terminal, -v", []string{"-v"}, true, false},
+		{"pipe", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--api-url", api.apiURL(), "--no-cache", "check", "nodejs", "22"}, tt.args...)
+			cmd := command(t, nil, args...)
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			master, tty := openPTY(t)
+			if tt.tty {
+				cmd.Stderr = tty
+			}
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("%s\nstderr: %s", err, stderr.String())
+			}
+			if tt.tty {
+				// Once no one has the terminal open, reading it ends in EIO
+				// instead of blocking.
+				tty.Close()
+				io.Copy(&stderr, master)
+			}
+
+			out := stderr.String()
+			if spinner := strings.Contains(out, "⠋ Checking nodejs 22"); spinner != tt.spinner {
+				t.Errorf("spinner shown = %t, want %t\nstderr: %q", spinner, tt.spinner, out)
+			}
+			if tt.spinner && !strings.HasSuffix(out, "\r\033[K") {
+				t.Errorf("stderr = %q, want the spinner line cleared at the end", out)
+			}
+			if !strings.HasPrefix(stdout.String(), "Nodejs 22 is not EOL yet.") {
+				t.Errorf("stdout = %q, want the result", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+var preciseDurations bool
+
+// EOL dates are plain dates, so which day it is decides the verdict. Days
+// start and end in UTC unless --tz names another zone, so a check gives the
+// same answer whatever timezone the machine running it is set to.
+var timezone string
+var location = time.UTC
+
+// loadTimezone applies --tz.
+func loadTimezone() error {
+	location = time.UTC
+	if timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("Invalid --tz %q, expected an IANA timezone like Europe/Prague", timezone)
+	}
+	location = loc
+	return nil
+}
+
+// clock tells the time; tests replace it to check what happens around
+// midnight.
+var clock = time.Now
+
+// now returns the current time in the --tz timezone.
+func now() time.Time {
+	return clock().In(location)
+}
+
+// parseDay parses a YYYY-MM-DD date as the start of that day in the --tz
+// timezone.
+func parseDay(date string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", date, location)
+}
+
+type Status = endoflife.Status
+
+// today returns the current date in the YYYY-MM-DD form the API uses.
+func today() string {
+	return now().Format("2006-01-02")
+}
+
+// eolText renders the EOL date for messages.
+func eolText(v SoftwareVersion) string {
+	if date := endoflife.EOLDate(v); date != "" {
+		return date
+	}
+	return "an unknown date"
+}
+
+// eolCell renders the EOL date for tables, where the API's booleans become
+// "yes" or "-".
+func eolCell(v SoftwareVersion) string {
+	switch eol := v.EOL.(type) {
+	case string:
+		return eol
+	case bool:
+		if eol {
+			return "yes"
+		}
+	}
+	return "-"
+}
+
+// supportEnded reports whether regular support for a cycle is over, either
+// because its support date has passed or because it has none at all. Support
+// given as true lasts until the cycle reaches EOL.
+func supportEnded(v SoftwareVersion, now string) bool {
+	switch supportValue := v.Support.(type) {
+	case string:
+		return supportValue <= now
+	case bool:
+		return !supportValue || endoflife.IsEOL(v, now)
+	}
+	return false
+}
+
+// eolWithin reports whether a cycle reaches EOL within the given number of days.
+func eolWithin(v SoftwareVersion, days int) bool {
+	eol, err := parseDay(endoflife.EOLDate(v))
+	if err != nil {
+		return false
+	}
+	return !eol.After(now().AddDate(0, 0, days))
+}
+
+// daysUntil returns the number of days from today until a YYYY-MM-DD date,
+// negative if it has passed.
+func daysUntil(date string) (int, bool) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0, false
+	}
+	now, _ := time.Parse("2006-01-02", today())
+	return int(t.Sub(now).Hours() / 24), true
+}
+
+// untilEOL returns the time from now until the start of a cycle's EOL day.
+func untilEOL(v SoftwareVersion) (time.Duration, bool) {
+	eol, err := parseDay(endoflife.EOLDate(v))
+	if err != nil {
+		return 0, false
+	}
+	return eol.Sub(now()), true
+}
+
+// formatDays renders a duration as whole days, or as days and hours with
+// --precise, since near the boundary the rounded form can be misleading.
+func formatDays(d time.Duration) string {
+	if preciseDurations {
+		hours := int(d.Hours())
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	}
+	days := int(d.Round(24*time.Hour).Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// eolTextWithDistance renders the EOL date along with how far away it is,
+// e.g. "2025-04-30 (in 89 days)".
+func eolTextWithDistance(v SoftwareVersion) string {
+	d, ok := untilEOL(v)
+	if !ok {
+		return eolText(v)
+	}
+	if d < 0 {
+		return fmt.Sprintf("%s (%s ago)", eolText(v), formatDays(-d))
+	}
+	return fmt.Sprintf("%s (in %s)", eolText(v), formatDays(d))
+}
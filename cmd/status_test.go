@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+func TestTimezoneDayBoundaries(t *testing.T) {
+	savedClock, savedTimezone := clock, timezone
+	t.Cleanup(func() {
+		clock, timezone = savedClock, savedTimezone
+		loadTimezone()
+	})
+
+	// Late on 30 April in UTC, which is still the evening before in New York
+	// and already the morning of 1 May in Tokyo.
+	instant := time.Date(2025, 4, 30, 23, 30, 0, 0, time.UTC)
+	clock = func() time.Time { return instant }
+	v := SoftwareVersion{Cycle: "1", EOL: "2025-05-01", Support: "2025-04-30"}
+
+	tests := []struct {
+		timezone  string
+		today     string
+		status    Status
+		daysToEOL int
+		within0   bool
+	}{
+		{"", "2025-04-30", endoflife.StatusMaintenance, 1, false},
+		{"UTC", "2025-04-30", endoflife.StatusMaintenance, 1, false},
+		{"America/New_York", "2025-04-30", endoflife.StatusMaintenance, 1, false},
+		{"Asia/Tokyo", "2025-05-01", endoflife.StatusEOL, 0, true},
+		{"Pacific/Kiritimati", "2025-05-01", endoflife.StatusEOL, 0, true},
+		{"Etc/GMT+12", "2025-04-30", endoflife.StatusMaintenance, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			timezone = tt.timezone
+			if err := loadTimezone(); err != nil {
+				t.Fatal(err)
+			}
+			if got := today(); got != tt.today {
+				t.Errorf("today() = %s, want %s", got, tt.today)
+			}
+			if got := endoflife.CycleStatus(v, today()); got != tt.status {
+				t.Errorf("status = %s, want %s", got, tt.status)
+			}
+			if got, _ := daysUntil("2025-05-01"); got != tt.daysToEOL {
+				t.Errorf("daysUntil = %d, want %d", got, tt.daysToEOL)
+			}
+			if got := eolWithin(v, 0); got != tt.within0 {
+				t.Errorf("eolWithin(0) = %t, want %t", got, tt.within0)
+			}
+		})
+	}
+}
+
+func TestInvalidTimezone(t *testing.T) {
+	savedTimezone := timezone
+	t.Cleanup(func() {
+		timezone = savedTimezone
+		loadTimezone()
+	})
+	timezone = "Nowhere/Special"
+	if err := loadTimezone(); err == nil {
+		t.Error("expected an error for an unknown timezone")
+	}
+	if location != time.UTC {
+		t.Errorf("location = %s after a failed --tz, want UTC", location)
+	}
+}
+
+func TestPreciseDurations(t *testing.T) {
+	savedClock, savedTimezone, savedPrecise := clock, timezone, preciseDurations
+	t.Cleanup(func() {
+		clock, timezone, preciseDurations = savedClock, savedTimezone, savedPrecise
+		loadTimezone()
+	})
+	timezone = "UTC"
+	if err := loadTimezone(); err != nil {
+		t.Fatal(err)
+	}
+	v := SoftwareVersion{Cycle: "1", EOL: "2025-02-01"}
+
+	tests := []struct {
+		now     time.Time
+		rounded string
+		precise string
+	}{
+		{time.Date(2025, 1, 30, 11, 0, 0, 0, time.UTC), "2025-02-01 (in 2 days)", "2025-02-01 (in 1d 13h)"},
+		// Past noon the rounded form drops a day while the precise one
+		// only loses two hours.
+		{time.Date(2025, 1, 30, 13, 0, 0, 0, time.UTC), "2025-02-01 (in 1 day)", "2025-02-01 (in 1d 11h)"},
+		{time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC), "2025-02-01 (in 0 days)", "2025-02-01 (in 0d 1h)"},
+		{time.Date(2025, 2, 1, 6, 30, 0, 0, time.UTC), "2025-02-01 (0 days ago)", "2025-02-01 (0d 6h ago)"},
+		{time.Date(2025, 2, 3, 18, 0, 0, 0, time.UTC), "2025-02-01 (3 days ago)", "2025-02-01 (2d 18h ago)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now.Format(time.RFC3339), func(t *testing.T) {
+			clock = func() time.Time { return tt.now }
+			preciseDurations = false
+			if got := eolTextWithDistance(v); got != tt.rounded {
+				t.Errorf("rounded = %q, want %q", got, tt.rounded)
+			}
+			preciseDurations = true
+			if got := eolTextWithDistance(v); got != tt.precise {
+				t.Errorf("--precise = %q, want %q", got, tt.precise)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import "testing"
+
+func TestStrict(t *testing.T) {
+	products := testProducts()
+	products["hugo"] = `[{"cycle":"0.120","releaseDate":"2023-10-30","latest":"0.120.4"}]`
+	products["deno"] = `[
+{"cycle":"2","releaseDate":"2024-10-09","support":"` + daysFromNow(300) + `","eol":"` + daysFromNow(400) + `"},
+{"cycle":"1","releaseDate":"2020-05-13","support":"` + daysFromNow(5) + `","eol":"` + daysFromNow(10) + `"}
+]`
+	api := newAPIServer(t, products)
+
+	tests := []struct {
+		name    string
+		product string
+		version string
+		code    int
+		stderr  string
+		// lenient is the exit code without --strict.
+		lenient int
+	}{
+		{"supported", "deno", "2", 0, "", 0},
+		{"unsupported", "nodejs", "20", 1, "Error: Nodejs 20 is not supported anymore\n", 0},
+		{"soon", "deno", "1", 1, "Error: Deno 1 reaches EOL within 30 days\n", 0},
+		{"unknown", "hugo", "0.120", 1, "Error: Hugo 0.120 has no known EOL date\n", 0},
+		{"eol", "nodejs", "18", 1, "Error: " + errEOL.Error() + "\n", 1},
+	}
+	for _, tt := range tests {
+		for _, flag := range []string{"--strict", "--fail-on-any"} {
+			t.Run(tt.name+" "+flag, func(t *testing.T) {
+				got := run(t, nil, "--api-url", api.apiURL(), "check", tt.product, tt.version, flag)
+				if got.code != tt.code {
+					t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+				}
+				if got.stderr != tt.stderr {
+					t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+				}
+			})
+		}
+		t.Run(tt.name+" lenient", func(t *testing.T) {
+			got := run(t, nil, "--api-url", api.apiURL(), "check", tt.product, tt.version)
+			if got.code != tt.lenient {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.lenient, got.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var supportedOnDate string
+var latestAsOfDate string
+
+// supportedOn returns the cycles that were released and not yet EOL on date.
+// Cycles that are EOL without a known date are left out, since we can't tell
+// when they stopped being supported.
+func supportedOn(versions []SoftwareVersion, date string) []SoftwareVersion {
+	var supported []SoftwareVersion
+	for _, v := range versions {
+		if v.ReleaseDate == "" || v.ReleaseDate > date {
+			continue
+		}
+		switch eol := v.EOL.(type) {
+		case string:
+			if eol > date {
+				supported = append(supported, v)
+			}
+		case bool:
+			if !eol {
+				supported = append(supported, v)
+			}
+		}
+	}
+	return supported
+}
+
+// latestAsOf returns the most recently released cycle as of date.
+func latestAsOf(versions []SoftwareVersion, date string) (SoftwareVersion, bool) {
+	var latest SoftwareVersion
+	found := false
+	for _, v := range versions {
+		if v.ReleaseDate == "" || v.ReleaseDate > date {
+			continue
+		}
+		if !found || v.ReleaseDate > latest.ReleaseDate {
+			latest, found = v, true
+		}
+	}
+	return latest, found
+}
+
+func validateDate(flag string, date string) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("Invalid --%s %q, expected YYYY-MM-DD", flag, date)
+	}
+	return nil
+}
+
+// supportedOnCmd represents the supported-on command
+var supportedOnCmd = &cobra.Command{
+	Use:   "supported-on <name>",
+	Short: "Show which cycles of a product were supported on a given date",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if supportedOnDate == "" && latestAsOfDate == "" {
+			return errors.New("Either --date or --latest-as-of is required")
+		}
+
+		versions, _, err := fetchCycles(cmd.Context(), name)
+		if err != nil {
+			return err
+		}
+
+		if latestAsOfDate != "" {
+			if err := validateDate("latest-as-of", latestAsOfDate); err != nil {
+				return err
+			}
+			v, ok := latestAsOf(versions, latestAsOfDate)
+			if !ok {
+				return fmt.Errorf("No %s cycle had been released by %s", name, latestAsOfDate)
+			}
+			fmt.Printf("The newest %s cycle on %s was %s (released %s)\n", capitalize(name), latestAsOfDate, v.Cycle, v.ReleaseDate)
+			return nil
+		}
+
+		if err := validateDate("date", supportedOnDate); err != nil {
+			return err
+		}
+		supported := supportedOn(versions, supportedOnDate)
+		if len(supported) == 0 {
+			return fmt.Errorf("No %s cycle was supported on %s", name, supportedOnDate)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CYCLE\tRELEASED\tEOL")
+		for _, v := range supported {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Cycle, v.ReleaseDate, eolCell(v))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(supportedOnCmd)
+
+	supportedOnCmd.Flags().StringVar(&supportedOnDate, "date", "", "Date (YYYY-MM-DD) to list the supported cycles for, e.g. a build date")
+	supportedOnCmd.Flags().StringVar(&latestAsOfDate, "latest-as-of", "", "Show the newest cycle that existed on this date (YYYY-MM-DD) instead")
+	supportedOnCmd.MarkFlagsMutuallyExclusive("date", "latest-as-of")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSupportedOn(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout []string
+		stderr string
+	}{
+		{"date", []string{"--date", "2023-06-01"}, 0, []string{
+			"CYCLE  RELEASED    EOL",
+			"20     2023-04-18  " + daysFromNow(2000),
+			"18     2022-04-19  2025-04-30",
+		}, ""},
+		{"date after an eol", []string{"--date", "2025-05-01"}, 0, []string{
+			"CYCLE  RELEASED    EOL",
+			"22     2024-04-24  " + daysFromNow(100),
+			"20     2023-04-18  " + daysFromNow(2000),
+		}, ""},
+		{"date before any release", []string{"--date", "2020-01-01"}, 1, nil, "Error: No nodejs cycle was supported on 2020-01-01\n"},
+		{"latest as of", []string{"--latest-as-of", "2024-01-15"}, 0, []string{
+			"The newest Nodejs cycle on 2024-01-15 was 20 (released 2023-04-18)",
+		}, ""},
+		{"latest as of a release day", []string{"--latest-as-of", "2024-04-24"}, 0, []string{
+			"The newest Nodejs cycle on 2024-04-24 was 22 (released 2024-04-24)",
+		}, ""},
+		{"latest as of before any release", []string{"--latest-as-of", "2021-12-31"}, 1, nil, "Error: No nodejs cycle had been released by 2021-12-31\n"},
+		{"invalid date", []string{"--date", "01/06/2023"}, 1, nil, "Error: Invalid --date \"01/06/2023\", expected YYYY-MM-DD\n"},
+		{"no date", nil, 1, nil, "Error: Either --date or --latest-as-of is required\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL(), "supported-on", "nodejs"}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			want := ""
+			if tt.stdout != nil {
+				want = strings.Join(tt.stdout, "\n") + "\n"
+			}
+			if got.stdout != want {
+				t.Errorf("stdout = %q, want %q", got.stdout, want)
+			}
+			if tt.stderr != "" && got.stderr != tt.stderr {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// timelineBar is where a cycle's lifecycle starts and changes on the chart.
+// A zero eol means the cycle has no EOL date yet and runs off the chart.
+// eolUndated marks a cycle that is EOL without a known date, whose bar ends
+// where its support did, or at its release if that isn't known either.
+type timelineBar struct {
+	cycle      string
+	released   time.Time
+	supportEnd time.Time
+	eol        time.Time
+	eolUndated bool
+}
+
+func newTimelineBar(v SoftwareVersion) (timelineBar, bool) {
+	released, err := parseDay(v.ReleaseDate)
+	if err != nil {
+		return timelineBar{}, false
+	}
+	bar := timelineBar{cycle: v.Cycle, released: released}
+	if eol, err := parseDay(endoflife.EOLDate(v)); err == nil {
+		bar.eol = eol
+	}
+	switch support := v.Support.(type) {
+	case string:
+		if end, err := parseDay(support); err == nil {
+			bar.supportEnd = end
+		}
+	case bool:
+		if !support {
+			bar.supportEnd = released
+		}
+	}
+	if eol, ok := v.EOL.(bool); ok && eol {
+		bar.eolUndated = true
+		bar.eol = bar.supportEnd
+		if bar.eol.IsZero() {
+			bar.eol = released
+		}
+	}
+	if bar.supportEnd.IsZero() {
+		bar.supportEnd = bar.eol
+	}
+	return bar, true
+}
+
+// terminalWidth returns the width of the terminal stdout is attached to, or
+// 80 columns when it isn't one.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+// writeTimeline draws each cycle as a bar from its release to its EOL date,
+// "=" while it gets regular support and "-" while it only gets security
+// fixes, with a "|" marking today. Bars of cycles that are EOL without a
+// known date end in "x".
+func writeTimeline(w io.Writer, bars []timelineBar, now time.Time, width int) {
+	start, end := now, now
+	labelWidth := 0
+	for _, bar := range bars {
+		if bar.released.Before(start) {
+			start = bar.released
+		}
+		if bar.eol.After(end) {
+			end = bar.eol
+		}
+		labelWidth = max(labelWidth, len(bar.cycle))
+	}
+	end = end.AddDate(0, 1, 0)
+
+	cols := max(width-labelWidth-2, 10)
+	column := func(t time.Time) int {
+		return int(float64(t.Sub(start)) / float64(end.Sub(start)) * float64(cols-1))
+	}
+	nowCol := column(now)
+
+	for _, bar := range bars {
+		row := []rune(strings.Repeat(" ", cols))
+		from, to := column(bar.released), cols-1
+		if !bar.eol.IsZero() {
+			to = column(bar.eol)
+		}
+		supportEnd := to
+		if !bar.supportEnd.IsZero() {
+			supportEnd = column(bar.supportEnd)
+		}
+		for i := from; i <= to; i++ {
+			if i < supportEnd {
+				row[i] = '='
+			} else {
+				row[i] = '-'
+			}
+		}
+		if bar.eol.IsZero() {
+			row[cols-1] = '>'
+		}
+		if bar.eolUndated {
+			row[to] = 'x'
+		}
+		row[nowCol] = '|'
+		fmt.Fprintf(w, "%-*s  %s\n", labelWidth, bar.cycle, string(row))
+	}
+
+	axis := []rune(strings.Repeat(" ", cols))
+	startLabel := []rune(start.Format("2006"))
+	copy(axis, startLabel)
+	endLabel := []rune(end.Format("2006"))
+	copy(axis[cols-len(endLabel):], endLabel)
+
+	nowLabel := []rune("^ now")
+	nowFrom := nowCol
+	if nowFrom+len(nowLabel) > cols {
+		nowLabel = []rune("now ^")
+		nowFrom = max(nowCol-len(nowLabel)+1, 0)
+	}
+	// When the marker would run into a year label, it goes on a line of its
+	// own instead.
+	if nowFrom > len(startLabel) && nowFrom+len(nowLabel) < cols-len(endLabel) {
+		copy(axis[nowFrom:], nowLabel)
+		fmt.Fprintf(w, "%-*s  %s\n", labelWidth, "", string(axis))
+		return
+	}
+	fmt.Fprintf(w, "%-*s  %s\n", labelWidth, "", string(axis))
+	fmt.Fprintf(w, "%-*s  %s%s\n", labelWidth, "", strings.Repeat(" ", nowFrom), string(nowLabel))
+}
+
+// timelineCmd represents the timeline command
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <name>",
+	Short: "Draw a chart of when each release cycle of a product is supported",
+	Long: `Draw a chart of when each release cycle of a product is supported, from its
+release to its EOL date: "=" while it gets regular support, "-" while it only
+gets security fixes and ">" if it has no EOL date yet. An "x" ends the bar of
+a cycle that is EOL without a known date. A "|" marks today.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versions, _, err := fetchCycles(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		if recentCycles > 0 {
+			versions = mostRecent(versions, recentCycles)
+		}
+
+		var bars []timelineBar
+		for _, v := range versions {
+			if bar, ok := newTimelineBar(v); ok {
+				bars = append(bars, bar)
+			}
+		}
+		if len(bars) == 0 {
+			return fmt.Errorf("%s has no cycles with a release date to draw", capitalize(args[0]))
+		}
+		// Oldest at the top, like a Gantt chart.
+		slices.SortStableFunc(bars, func(a, b timelineBar) int {
+			return a.released.Compare(b.released)
+		})
+
+		today, _ := parseDay(today())
+		writeTimeline(os.Stdout, bars, today, terminalWidth())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timelineCmd)
+
+	timelineCmd.Flags().IntVar(&recentCycles, "recent", 0, "Only draw the N most recently released cycles")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTimelineBar(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tests := []struct {
+		name string
+		v    SoftwareVersion
+		want timelineBar
+	}{
+		{
+			name: "dates",
+			v:    SoftwareVersion{Cycle: "20", ReleaseDate: "2023-04-18", Support: "2024-10-22", EOL: "2026-04-30"},
+			want: timelineBar{cycle: "20", released: day("2023-04-18"), supportEnd: day("2024-10-22"), eol: day("2026-04-30")},
+		},
+		{
+			name: "no EOL date yet",
+			v:    SoftwareVersion{Cycle: "1.23", ReleaseDate: "2024-08-13", EOL: false},
+			want: timelineBar{cycle: "1.23", released: day("2024-08-13")},
+		},
+		{
+			name: "EOL without a date ends at the support end",
+			v:    SoftwareVersion{Cycle: "5", ReleaseDate: "2020-01-01", Support: "2021-06-01", EOL: true},
+			want: timelineBar{cycle: "5", released: day("2020-01-01"), supportEnd: day("2021-06-01"), eol: day("2021-06-01"), eolUndated: true},
+		},
+		{
+			name: "EOL without any end date ends at the release",
+			v:    SoftwareVersion{Cycle: "4", ReleaseDate: "2019-01-01", EOL: true},
+			want: timelineBar{cycle: "4", released: day("2019-01-01"), supportEnd: day("2019-01-01"), eol: day("2019-01-01"), eolUndated: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := newTimelineBar(tt.v)
+			if !ok || got != tt.want {
+				t.Errorf("got %+v, %t, want %+v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTimeline(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	bars := []timelineBar{
+		{cycle: "4", released: day("2019-01-01"), supportEnd: day("2020-01-01"), eol: day("2020-01-01"), eolUndated: true},
+		{cycle: "5", released: day("2020-01-01"), supportEnd: day("2025-01-01"), eol: day("2026-12-01")},
+	}
+
+	var out strings.Builder
+	writeTimeline(&out, bars, day("2026-11-01"), 60)
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+
+	if row := strings.TrimRight(lines[0], " |"); !strings.HasSuffix(row, "x") || strings.Contains(lines[0], ">") {
+		t.Errorf("EOL bar without a date should end in x, got %q", lines[0])
+	}
+	axis := lines[2]
+	if !strings.HasPrefix(strings.TrimSpace(axis), "2019") || !strings.HasSuffix(axis, "2027") {
+		t.Errorf("year labels were overwritten: %q", axis)
+	}
+	if len(lines) != 4 || !strings.HasSuffix(lines[3], "now ^") {
+		t.Errorf("expected the now marker on its own line, got %q", out.String())
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+)
+
+var trace bool
+
+// traceBodyLimit caps how much of a response body --trace prints.
+const traceBodyLimit = 2048
+
+var verbose bool
+
+// logf prints a diagnostic line to stderr under -v (or --trace).
+func logf(format string, args ...interface{}) {
+	if verbose || trace {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
+// traceRequest dumps an outgoing request, with the values of the headers we
+// added redacted.
+func traceRequest(req *http.Request, added http.Header) {
+	if !trace {
+		return
+	}
+	clean := req.Clone(req.Context())
+	clean.Header = redactHeaders(req.Header, added)
+	dump, err := httputil.DumpRequestOut(clean, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: could not dump request: %s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "> %s %s\n> %s", req.Method, req.URL, dump)
+}
+
+func traceResponse(resp *http.Response, body []byte) {
+	if !trace {
+		return
+	}
+	dump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: could not dump response: %s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "< %s", dump)
+	if len(body) > traceBodyLimit {
+		fmt.Fprintf(os.Stderr, "%s\n... (%d more bytes)\n\n", body[:traceBodyLimit], len(body)-traceBodyLimit)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s\n\n", body)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	products := testProducts()
+	var cycles []string
+	for i := 0; i < 100; i++ {
+		cycles = append(cycles, fmt.Sprintf(`{"cycle":"%d","releaseDate":"2020-01-01","eol":"2021-01-01","latest":"%d.0.1"}`, i, i))
+	}
+	products["big"] = "[" + strings.Join(cycles, ",") + "]"
+	api := newAPIServer(t, products)
+
+	t.Run("request and response", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "--trace", "check", "nodejs", "22")
+		if got.code != 0 {
+			t.Fatalf("exit code = %d\nstderr: %s", got.code, got.stderr)
+		}
+		for _, want := range []string{
+			"> GET " + api.apiURL() + "nodejs.json\n",
+			"< HTTP/1.1 200 OK\r\n",
+			`"latest":"22.9.0"`,
+		} {
+			if !strings.Contains(got.stderr, want) {
+				t.Errorf("stderr = %q, want it to contain %q", got.stderr, want)
+			}
+		}
+	})
+
+	t.Run("body truncated", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "--trace", "check", "big", "1")
+		want := fmt.Sprintf("\n... (%d more bytes)\n", len(products["big"])-traceBodyLimit)
+		if !strings.Contains(got.stderr, want) {
+			t.Errorf("stderr = %q, want it to contain %q", got.stderr, want)
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		got := run(t, nil, "--api-url", api.apiURL(), "check", "nodejs", "22")
+		if strings.Contains(got.stderr, "> GET") || strings.Contains(got.stderr, "< HTTP") {
+			t.Errorf("stderr = %q, want no trace", got.stderr)
+		}
+	})
+}
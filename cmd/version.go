@@ -0,0 +1,45 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/filiptronicek/date-reaper/endoflife"
+)
+
+// matchGranularity and matchPick are --match-granularity and --match-pick,
+// see endoflife.MatchOptions.
+var matchGranularity string
+var matchPick string
+
+// validateMatchGranularity makes sure --match-granularity is one we know.
+func validateMatchGranularity() error {
+	if matchGranularity != "" && !slices.Contains(endoflife.Granularities, matchGranularity) {
+		return fmt.Errorf("Invalid --match-granularity %q, expected one of %s", matchGranularity, strings.Join(endoflife.Granularities, ", "))
+	}
+	if !slices.Contains(endoflife.Picks, matchPick) {
+		return fmt.Errorf("Invalid --match-pick %q, expected one of %s", matchPick, strings.Join(endoflife.Picks, ", "))
+	}
+	return nil
+}
+
+// matchCycle finds the release cycle a version belongs to, as tuned by
+// --match-granularity and --match-pick, see endoflife.MatchCycle.
+func matchCycle(versions []SoftwareVersion, version string) (SoftwareVersion, bool) {
+	return endoflife.MatchCycle(versions, version, endoflife.MatchOptions{Granularity: matchGranularity, Pick: matchPick})
+}
+
+// behindLatest reports whether a full version (not just the cycle name) is
+// older than the newest release of its cycle. A version no longer than the
+// cycle, like 18 matching the 18.1 cycle, doesn't pin a release at all.
+func behindLatest(v SoftwareVersion, version string) bool {
+	if version == v.Cycle || strings.HasPrefix(v.Cycle, version+".") || v.Latest == "" {
+		return false
+	}
+	return endoflife.CompareVersions(version, v.Latest) < 0
+}
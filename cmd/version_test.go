@@ -0,0 +1,69 @@
+package cmd
+
+import "testing"
+
+func TestBehindLatest(t *testing.T) {
+	tests := []struct {
+		name    string
+		cycle   string
+		latest  string
+		version string
+		want    bool
+	}{
+		{"cycle itself", "18", "18.20.4", "18", false},
+		{"old patch", "18", "18.20.4", "18.0.0", true},
+		{"latest patch", "18", "18.20.4", "18.20.4", false},
+		{"bare major matching a minor cycle", "18.1", "18.1.3", "18", false},
+		{"old patch of a minor cycle", "18.1", "18.1.3", "18.1.0", true},
+		{"no latest known", "18", "", "18.0.0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := SoftwareVersion{Cycle: tt.cycle, Latest: tt.latest}
+			if got := behindLatest(v, tt.version); got != tt.want {
+				t.Errorf("behindLatest(%s, %q) = %t, want %t", tt.cycle, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchCycle(t *testing.T) {
+	cycles := []SoftwareVersion{{Cycle: "20"}, {Cycle: "18.1"}, {Cycle: "18.0"}, {Cycle: "16"}}
+	tests := []struct {
+		version     string
+		granularity string
+		pick        string
+		want        string
+	}{
+		{"20", "", "newest", "20"},
+		{"20.11.1", "", "newest", "20"},
+		{"18", "", "newest", "18.1"},
+		{"18", "", "oldest", "18.0"},
+		{"18.0.3", "", "newest", "18.0"},
+		{"16.20", "major", "newest", "16"},
+		{"20", "major", "newest", "20"},
+		{"18.1.2", "major", "newest", ""},
+		{"18", "major", "newest", ""},
+		{"18", "major", "oldest", ""},
+		{"18.1.2", "minor", "newest", "18.1"},
+		{"18.0", "minor", "oldest", "18.0"},
+		{"20.11.1", "minor", "newest", ""},
+		{"18", "minor", "newest", ""},
+		{"18", "exact", "newest", ""},
+		{"18.1", "exact", "newest", "18.1"},
+		{"16", "", "oldest", "16"},
+		{"16.20.2", "", "oldest", "16"},
+		{"18.2", "", "newest", ""},
+		{"14", "", "newest", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.granularity+" "+tt.pick, func(t *testing.T) {
+			matchGranularity, matchPick = tt.granularity, tt.pick
+			defer func() { matchGranularity, matchPick = "", "newest" }()
+			v, ok := matchCycle(cycles, tt.version)
+			if got := v.Cycle; got != tt.want || ok != (tt.want != "") {
+				t.Errorf("matchCycle(%q) = %q, %t, want %q", tt.version, got, ok, tt.want)
+			}
+		})
+	}
+}
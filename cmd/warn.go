@@ -0,0 +1,48 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+)
+
+// warnExitCode is the code a run exits with when nothing failed but some
+// versions reach EOL within --soon-days. Zero keeps such runs passing. CI
+// systems like Buildkite treat a specific code (commonly 78) as neutral.
+var warnExitCode int
+
+// validateWarnExitCode keeps --warn-exit-code clear of the codes that
+// already mean something.
+func validateWarnExitCode() error {
+	if warnExitCode < 0 || warnExitCode > 255 {
+		return fmt.Errorf("Invalid --warn-exit-code %d, expected 0-255", warnExitCode)
+	}
+	if warnExitCode == exitFailure || warnExitCode == exitIncomplete || warnExitCode == exitNetworkUsed {
+		return fmt.Errorf("Invalid --warn-exit-code %d, it already means the run failed, was incomplete or used the network", warnExitCode)
+	}
+	return nil
+}
+
+// soonWarning returns the --warn-exit-code error for a run that passed but
+// has versions reaching EOL soon, or nil if there's nothing to warn about.
+func soonWarning(results []Result) error {
+	if warnExitCode == 0 {
+		return nil
+	}
+	soon := 0
+	for _, r := range results {
+		if r.Error == "" && slices.Contains(resultStates(r), stateSoon) {
+			soon++
+		}
+	}
+	if soon == 0 {
+		return nil
+	}
+	return &exitError{
+		code: warnExitCode,
+		err:  fmt.Errorf("%d version(s) reach EOL within %d days", soon, soonDays),
+	}
+}
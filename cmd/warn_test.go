@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWarnExitCode(t *testing.T) {
+	api := newAPIServer(t, testProducts())
+	inventory := writeFile(t, "inventory.yaml", "- product: nodejs\n  version: \"22\"\n- product: nodejs\n  version: \"18\"\n")
+
+	// nodejs 22 reaches EOL in about 100 days and 18 is EOL.
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"neutral", []string{"check", "nodejs", "22", "--soon-days", "120"}, 0, ""},
+		{"warning", []string{"--warn-exit-code", "78", "check", "nodejs", "22", "--soon-days", "120"}, 78, "Error: 1 version(s) reach EOL within 120 days\n"},
+		{"nothing to warn about", []string{"--warn-exit-code", "78", "check", "nodejs", "22"}, 0, ""},
+		{"failure beats warning", []string{"--warn-exit-code", "78", "check", "nodejs", "18", "--soon-days", "120"}, 1, "Error: EOL\n"},
+		{"bulk failure beats warning", []string{"--warn-exit-code", "78", "check-inventory", "--soon-days", "120", inventory}, 1, "Error: 1 version(s) are EOL\n"},
+		{"code that means something else", []string{"--warn-exit-code", "3", "check", "nodejs", "22"}, 1, "Error: Invalid --warn-exit-code 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, nil, append([]string{"--api-url", api.apiURL()}, tt.args...)...)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+			if tt.stderr == "" && got.stderr != "" || !strings.Contains(got.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", got.stderr, tt.stderr)
+			}
+			if strings.Count(got.stderr, "Error:") > 1 || strings.Contains(got.stderr, "Usage:") {
+				t.Errorf("stderr = %q, want the error once and no usage", got.stderr)
+			}
+		})
+	}
+}
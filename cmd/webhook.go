@@ -0,0 +1,83 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var webhookURL string
+
+// FileDigest sums up the results of one file in a Digest.
+type FileDigest struct {
+	Path    string `json:"path"`
+	Checked int    `json:"checked"`
+	EOL     int    `json:"eol"`
+	Errors  int    `json:"errors,omitempty"`
+}
+
+// Digest is the one payload posted to --webhook at the end of a run over
+// many files, instead of a notification per file. Incomplete marks the
+// digest of a run cut short, which only covers the files checked.
+type Digest struct {
+	Tool       string       `json:"tool"`
+	Files      []FileDigest `json:"files"`
+	TotalEOL   int          `json:"totalEol"`
+	Incomplete bool         `json:"incomplete,omitempty"`
+}
+
+func (d *Digest) add(path string, results []Result) {
+	file := FileDigest{Path: path, Checked: len(results)}
+	for _, r := range results {
+		if r.Error != "" {
+			file.Errors++
+		}
+		if r.IsEOL {
+			file.EOL++
+		}
+	}
+	d.Files = append(d.Files, file)
+	d.TotalEOL += file.EOL
+}
+
+// webhookTimeout bounds sending the digest, which still happens after the
+// run's context was cancelled.
+const webhookTimeout = 10 * time.Second
+
+// postDigest sends the digest to --webhook as JSON. It doesn't stop when ctx
+// is cancelled, since the digest of a run cut short by --deadline or Ctrl-C
+// is the one most worth sending.
+func postDigest(ctx context.Context, digest Digest) error {
+	payload, err := json.Marshal(digest)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookTimeout)
+	defer cancel()
+
+	httpClient := &http.Client{}
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Error sending the webhook: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "date-reaper-cli")
+	logf("POST %s", webhookURL)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error sending the webhook: %s", err)
+	}
+	resp.Body.Close()
+	logf("%s: %d", webhookURL, resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Error sending the webhook: server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
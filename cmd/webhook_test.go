@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWebhookDigest(t *testing.T) {
+	tests := []struct {
+		name           string
+		slow           bool
+		code           int
+		wantIncomplete bool
+	}{
+		{"complete run", false, 0, false},
+		{"run cut short by --deadline", true, exitIncomplete, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPIServer(t, testProducts())
+			if tt.slow {
+				// Past the product check, so the deadline hits the variants.
+				api.slow("nodejs", 1, 5*time.Second)
+			}
+			digests := make(chan Digest, 1)
+			hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var digest Digest
+				if err := json.Unmarshal(body, &digest); err != nil {
+					t.Errorf("invalid digest %q: %s", body, err)
+				}
+				digests <- digest
+			}))
+			defer hook.Close()
+
+			chunk := writeFile(t, "chunk.yaml", "variants:\n  - name: \"18\"\n  - name: \"22\"\n")
+			got := run(t, nil, "--api-url", api.apiURL(), "--deadline", "500ms", "--no-cache", "check-chunk", "--tool", "nodejs", "--webhook", hook.URL, chunk)
+			if got.code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", got.code, tt.code, got.stderr)
+			}
+
+			select {
+			case digest := <-digests:
+				if digest.Incomplete != tt.wantIncomplete {
+					t.Errorf("incomplete = %t, want %t", digest.Incomplete, tt.wantIncomplete)
+				}
+				if len(digest.Files) != 1 || filepath.Base(digest.Files[0].Path) != "chunk.yaml" {
+					t.Errorf("files = %+v, want chunk.yaml", digest.Files)
+				}
+			default:
+				t.Fatal("no digest was posted")
+			}
+		})
+	}
+}
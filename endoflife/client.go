@@ -0,0 +1,215 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+package endoflife
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is where the endoflife.date API lives.
+const DefaultBaseURL = "https://endoflife.date/api/"
+
+// ErrVersionNotFound is what looking up a version the product's data doesn't
+// list fails with.
+var ErrVersionNotFound = errors.New("Version not found")
+
+// Options configure a Client. The zero value talks to the API with
+// http.DefaultClient, caches product data for the lifetime of the Client and
+// decides statuses by the current day in UTC.
+type Options struct {
+	// HTTPClient makes the API requests. Nil uses http.DefaultClient.
+	HTTPClient *http.Client
+	// Fetch, when set, looks product data up instead of the API requests,
+	// e.g. to read it from a mirror on disk. It gets the name of a product
+	// and returns the API's JSON response for it.
+	Fetch func(ctx context.Context, name string) ([]byte, Provenance, error)
+	// TTL is how long product data is cached in memory. Zero caches it for
+	// the lifetime of the Client, and a negative TTL doesn't cache it at
+	// all, leaving caching to Fetch.
+	TTL time.Duration
+	// Timeout bounds each lookup of a product. A lookup is shared by all
+	// callers asking for the product at the same time, so it doesn't stop
+	// when the one that started it gives up. Zero means no timeout.
+	Timeout time.Duration
+	// Location is the timezone whose current day decides statuses, since
+	// EOL dates are plain dates. Nil is UTC.
+	Location *time.Location
+	// Match tunes which cycle a version belongs to, see MatchCycle.
+	Match MatchOptions
+	// IncludeProvenance adds where the data came from to each Result.
+	IncludeProvenance bool
+}
+
+// Client looks up product versions, for using date-reaper as a library:
+//
+//	client := endoflife.NewClient(endoflife.DefaultBaseURL, endoflife.Options{})
+//	result, err := client.Check(ctx, "nodejs", "18")
+//
+// A Client is safe for concurrent use by multiple goroutines, and is meant to
+// be shared. All of them go through one HTTP client and one in-memory cache
+// of product data, and concurrent lookups of the same product share a single
+// request, so checking nodejs 18 and nodejs 20 side by side fetches nodejs
+// once. Failed lookups aren't cached.
+type Client struct {
+	opts Options
+
+	mu       sync.Mutex
+	cached   map[string]cachedProduct
+	inflight map[string]*productFetch
+}
+
+type cachedProduct struct {
+	body       []byte
+	provenance Provenance
+	storedAt   time.Time
+}
+
+// productFetch is a product lookup in flight, which concurrent lookups of the
+// same product wait for instead of fetching it again.
+type productFetch struct {
+	done       chan struct{}
+	body       []byte
+	provenance Provenance
+	err        error
+}
+
+// NewClient returns a Client for the API at baseURL, which is unused when
+// opts.Fetch is set.
+func NewClient(baseURL string, opts Options) *Client {
+	if opts.Fetch == nil {
+		opts.Fetch = apiFetch(baseURL, opts.HTTPClient)
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &Client{
+		opts:     opts,
+		cached:   map[string]cachedProduct{},
+		inflight: map[string]*productFetch{},
+	}
+}
+
+// apiFetch returns the Fetch of a Client requesting products from the API at
+// baseURL.
+func apiFetch(baseURL string, httpClient *http.Client) func(ctx context.Context, name string) ([]byte, Provenance, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return func(ctx context.Context, name string) ([]byte, Provenance, error) {
+		url := baseURL + "/" + name + ".json"
+		provenance := Provenance{URL: url, FetchedAt: time.Now()}
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, provenance, err
+		}
+		req.Header.Set("User-Agent", "date-reaper-cli")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, provenance, err
+		}
+		defer resp.Body.Close()
+		provenance.Status = resp.StatusCode
+		if resp.StatusCode != http.StatusOK {
+			return nil, provenance, fmt.Errorf("Error: Server returned status %d", resp.StatusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		return body, provenance, err
+	}
+}
+
+// Product returns the raw API response for a product, from the Client's
+// cache, a lookup already in flight or a new one. Callers must not modify the
+// returned body, since it is shared. When ctx is done first, Product returns
+// its error, while the lookup goes on for anyone else waiting for it.
+func (c *Client) Product(ctx context.Context, name string) ([]byte, Provenance, error) {
+	c.mu.Lock()
+	if entry, ok := c.cached[name]; ok && (c.opts.TTL == 0 || time.Since(entry.storedAt) < c.opts.TTL) {
+		c.mu.Unlock()
+		return entry.body, entry.provenance, nil
+	}
+	fetch, ok := c.inflight[name]
+	if !ok {
+		fetch = &productFetch{done: make(chan struct{})}
+		c.inflight[name] = fetch
+		go c.lookUp(ctx, name, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-fetch.done:
+		return fetch.body, fetch.provenance, fetch.err
+	case <-ctx.Done():
+		return nil, Provenance{}, ctx.Err()
+	}
+}
+
+// lookUp fetches a product for everyone waiting for it. It runs detached from
+// the context of the caller that started it, keeping only its values, and is
+// bounded by the Client's Timeout instead.
+func (c *Client) lookUp(ctx context.Context, name string, fetch *productFetch) {
+	ctx = context.WithoutCancel(ctx)
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+	body, provenance, err := c.opts.Fetch(ctx, name)
+
+	c.mu.Lock()
+	fetch.body, fetch.provenance, fetch.err = body, provenance, err
+	delete(c.inflight, name)
+	if err == nil && c.opts.TTL >= 0 {
+		c.cached[name] = cachedProduct{body, provenance, time.Now()}
+	}
+	c.mu.Unlock()
+	close(fetch.done)
+}
+
+// Cycles returns all release cycles of a product.
+func (c *Client) Cycles(ctx context.Context, name string) ([]SoftwareVersion, Provenance, error) {
+	body, provenance, err := c.Product(ctx, name)
+	if err != nil {
+		return nil, provenance, err
+	}
+
+	var versions []SoftwareVersion
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return nil, provenance, err
+	}
+	return versions, provenance, nil
+}
+
+// Check looks up the release cycle of a product version and builds its
+// result, which carries any lookup error too.
+func (c *Client) Check(ctx context.Context, name string, version string) (Result, error) {
+	cycles, provenance, err := c.Cycles(ctx, name)
+	if err == nil {
+		v, ok := MatchCycle(cycles, version, c.opts.Match)
+		if ok {
+			result := NewResult(name, version, v, time.Now().In(c.opts.Location).Format("2006-01-02"))
+			c.addProvenance(&result, provenance)
+			return result, nil
+		}
+		err = ErrVersionNotFound
+	}
+	result := Result{Product: name, Version: version, Error: err.Error()}
+	c.addProvenance(&result, provenance)
+	return result, err
+}
+
+func (c *Client) addProvenance(result *Result, provenance Provenance) {
+	if c.opts.IncludeProvenance {
+		result.Provenance = &provenance
+	}
+}
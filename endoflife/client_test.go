@@ -0,0 +1,249 @@
+package endoflife
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func daysFromNow(n int) string {
+	return time.Now().UTC().AddDate(0, 0, n).Format("2006-01-02")
+}
+
+var testProducts = map[string]string{
+	"nodejs": `[
+{"cycle":"22","releaseDate":"2024-04-24","lts":"2024-10-29","support":"` + daysFromNow(50) + `","eol":"` + daysFromNow(100) + `","latest":"22.9.0"},
+{"cycle":"20","releaseDate":"2023-04-18","lts":"2023-10-24","support":"2024-10-22","eol":"` + daysFromNow(2000) + `","latest":"20.17.0"},
+{"cycle":"18","releaseDate":"2022-04-19","lts":"2022-10-25","support":"2023-10-18","eol":"2025-04-30","latest":"18.20.4"}
+]`,
+	"python": `[
+{"cycle":"3.12","releaseDate":"2023-10-02","lts":false,"support":"2025-04-02","eol":"` + daysFromNow(1500) + `","latest":"3.12.7"},
+{"cycle":"3.9","releaseDate":"2020-10-05","lts":false,"support":"2022-05-17","eol":"2025-10-31","latest":"3.9.20"}
+]`,
+}
+
+// apiServer serves testProducts, each response taking delay, and counts the
+// requests for each product.
+type apiServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func newAPIServer(t *testing.T, delay time.Duration) *apiServer {
+	t.Helper()
+	s := &apiServer{requests: map[string]int{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".json")
+		s.mu.Lock()
+		s.requests[name]++
+		s.mu.Unlock()
+		time.Sleep(delay)
+		body, ok := testProducts[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *apiServer) count(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[name]
+}
+
+// TestClientConcurrentCheck hammers one Client from many goroutines, which
+// `go test -race` checks for data races. Every product is fetched once.
+func TestClientConcurrentCheck(t *testing.T) {
+	// Slow enough that lookups of the same product overlap.
+	api := newAPIServer(t, 50*time.Millisecond)
+	client := NewClient(api.URL+"/", Options{HTTPClient: api.Client()})
+
+	checks := []struct {
+		product string
+		version string
+		status  Status
+		err     bool
+	}{
+		{"nodejs", "22", StatusSupported, false},
+		{"nodejs", "20.1.0", StatusMaintenance, false},
+		{"nodejs", "18", StatusEOL, false},
+		{"python", "3.12", StatusMaintenance, false},
+		{"python", "3.9", StatusEOL, false},
+		{"python", "2.7", "", true},
+		{"nope", "1", "", true},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50*len(checks))
+	for i := 0; i < 50; i++ {
+		for _, c := range checks {
+			c := c
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := client.Check(context.Background(), c.product, c.version)
+				switch {
+				case (err != nil) != c.err:
+					errs <- fmt.Errorf("%s %s: error = %v, want error: %t", c.product, c.version, err, c.err)
+				case result.Product != c.product || result.Version != c.version || result.Status != c.status:
+					errs <- fmt.Errorf("%s %s: got %+v, want status %q", c.product, c.version, result, c.status)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, product := range []string{"nodejs", "python"} {
+		if n := api.count(product); n != 1 {
+			t.Errorf("%s was fetched %d times, want once", product, n)
+		}
+	}
+}
+
+func TestClientTTL(t *testing.T) {
+	tests := []struct {
+		ttl     time.Duration
+		fetches int
+	}{
+		{0, 1},
+		{time.Hour, 1},
+		{time.Millisecond, 2},
+		{-1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ttl.String(), func(t *testing.T) {
+			api := newAPIServer(t, 0)
+			client := NewClient(api.URL, Options{TTL: tt.ttl})
+			for i := 0; i < 2; i++ {
+				if _, err := client.Check(context.Background(), "nodejs", "22"); err != nil {
+					t.Fatal(err)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if n := api.count("nodejs"); n != tt.fetches {
+				t.Errorf("nodejs was fetched %d times, want %d", n, tt.fetches)
+			}
+		})
+	}
+}
+
+// TestClientFirstCallerCancels checks that a lookup started by a caller that
+// gives up still completes for the others waiting for it.
+func TestClientFirstCallerCancels(t *testing.T) {
+	api := newAPIServer(t, 100*time.Millisecond)
+	client := NewClient(api.URL, Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := client.Check(ctx, "nodejs", "22")
+		first <- err
+	}()
+	// Let the first caller start the lookup before the second joins it.
+	for api.count("nodejs") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan error)
+	go func() {
+		result, err := client.Check(context.Background(), "nodejs", "22")
+		if err == nil && result.Status != StatusSupported {
+			err = fmt.Errorf("status = %s, want %s", result.Status, StatusSupported)
+		}
+		second <- err
+	}()
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller: error = %v, want %v", err, context.Canceled)
+	}
+	if err := <-second; err != nil {
+		t.Errorf("second caller: %s", err)
+	}
+	if n := api.count("nodejs"); n != 1 {
+		t.Errorf("nodejs was fetched %d times, want once", n)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	api := newAPIServer(t, 200*time.Millisecond)
+	client := NewClient(api.URL, Options{Timeout: 20 * time.Millisecond})
+	_, err := client.Check(context.Background(), "nodejs", "22")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	api := newAPIServer(t, 0)
+	// Tokyo is already in the next day for part of every UTC day, which
+	// mustn't matter for a date this far away.
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		version string
+		eol     string
+		err     error
+	}{
+		{"bare major matches the newest cycle below it", Options{}, "3", daysFromNow(1500), nil},
+		{"oldest pick", Options{Match: MatchOptions{Pick: "oldest"}}, "3", "2025-10-31", nil},
+		{"major granularity", Options{Match: MatchOptions{Granularity: "major"}}, "3.12.1", "", ErrVersionNotFound},
+		{"minor granularity", Options{Match: MatchOptions{Granularity: "minor"}}, "3.12.1", daysFromNow(1500), nil},
+		{"location", Options{Location: tokyo}, "3.12", daysFromNow(1500), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(api.URL, tt.opts)
+			result, err := client.Check(context.Background(), "python", tt.version)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if result.EOL != tt.eol {
+				t.Errorf("EOL = %q, want %q", result.EOL, tt.eol)
+			}
+			if result.Provenance != nil {
+				t.Errorf("provenance = %+v without IncludeProvenance", result.Provenance)
+			}
+		})
+	}
+
+	client := NewClient(api.URL, Options{IncludeProvenance: true})
+	result, _ := client.Check(context.Background(), "python", "2.7")
+	if result.Error != ErrVersionNotFound.Error() || result.Provenance == nil || result.Provenance.URL != api.URL+"/python.json" {
+		t.Errorf("result = %+v, want a missing version with provenance", result)
+	}
+}
+
+func TestClientFetch(t *testing.T) {
+	var calls int
+	client := NewClient("", Options{Fetch: func(ctx context.Context, name string) ([]byte, Provenance, error) {
+		calls++
+		return []byte(testProducts[name]), Provenance{URL: "file:" + name}, nil
+	}})
+	versions, provenance, err := client.Cycles(context.Background(), "nodejs")
+	if err != nil || len(versions) != 3 || provenance.URL != "file:nodejs" {
+		t.Errorf("Cycles = %d cycles, %+v, %v", len(versions), provenance, err)
+	}
+	if _, _, err := client.Cycles(context.Background(), "nodejs"); err != nil || calls != 1 {
+		t.Errorf("fetched %d times (error %v), want the second lookup cached", calls, err)
+	}
+}
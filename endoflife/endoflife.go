@@ -0,0 +1,247 @@
+/*
+Copyright © 2023 Filip Troníček
+*/
+
+// Package endoflife looks up the release cycles of products in the
+// endoflife.date API and works out where a version is in its lifecycle.
+package endoflife
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SoftwareVersion is a release cycle of a product as the API describes it.
+// Support, EOL and LTS are either a YYYY-MM-DD date or a boolean.
+type SoftwareVersion struct {
+	Cycle             string      `json:"cycle"`
+	ReleaseDate       string      `json:"releaseDate"`
+	Support           interface{} `json:"support"`
+	EOL               interface{} `json:"eol"`
+	Latest            string      `json:"latest"`
+	LatestReleaseDate string      `json:"latestReleaseDate"`
+	LTS               interface{} `json:"lts"`
+}
+
+// Provenance records where the data behind a result came from.
+type Provenance struct {
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	FromCache bool      `json:"fromCache"`
+}
+
+// Status is where a release cycle is in its lifecycle.
+type Status string
+
+const (
+	// StatusSupported cycles still get regular updates.
+	StatusSupported Status = "supported"
+	// StatusMaintenance cycles are past their active support date and only
+	// get security fixes until they reach EOL.
+	StatusMaintenance Status = "maintenance"
+	// StatusEOL cycles don't get any updates anymore.
+	StatusEOL Status = "eol"
+	// StatusUnknown cycles come without any EOL information.
+	StatusUnknown Status = "unknown"
+)
+
+// Result is the machine-readable outcome of checking a single version.
+// Source, FailedBy and Snapshot are only filled in by the date-reaper
+// commands: where the version was found, the flag or condition that made it
+// fail the run, and the date of the embedded data it was checked against.
+type Result struct {
+	Source     string      `json:"source,omitempty"`
+	Product    string      `json:"product"`
+	Version    string      `json:"version"`
+	EOL        string      `json:"eol,omitempty"`
+	Support    string      `json:"support,omitempty"`
+	Status     Status      `json:"status,omitempty"`
+	IsEOL      bool        `json:"isEol"`
+	Error      string      `json:"error,omitempty"`
+	FailedBy   string      `json:"failedBy,omitempty"`
+	Snapshot   string      `json:"snapshot,omitempty"`
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// NewResult builds the result of a product version that matched the cycle v,
+// on the given YYYY-MM-DD day.
+func NewResult(name string, version string, v SoftwareVersion, today string) Result {
+	status := CycleStatus(v, today)
+	return Result{
+		Product: name,
+		Version: version,
+		EOL:     EOLDate(v),
+		Support: SupportEndDate(v),
+		Status:  status,
+		IsEOL:   status == StatusEOL,
+	}
+}
+
+// EOLDate returns a cycle's EOL date, or "" when the API only says whether it
+// is EOL.
+func EOLDate(v SoftwareVersion) string {
+	if date, ok := v.EOL.(string); ok {
+		return date
+	}
+	return ""
+}
+
+// IsEOL reports whether a cycle is EOL on the given YYYY-MM-DD day.
+func IsEOL(v SoftwareVersion, today string) bool {
+	switch eol := v.EOL.(type) {
+	case string:
+		return eol <= today
+	case bool:
+		return eol
+	}
+	return false
+}
+
+// CycleStatus works out a cycle's status on the given YYYY-MM-DD day. A cycle
+// is in maintenance mode once its support date has passed, or its support is
+// given as false, but its EOL date hasn't.
+func CycleStatus(v SoftwareVersion, today string) Status {
+	if IsEOL(v, today) {
+		return StatusEOL
+	}
+	if v.EOL == nil {
+		return StatusUnknown
+	}
+	switch support := v.Support.(type) {
+	case string:
+		if support <= today {
+			return StatusMaintenance
+		}
+	case bool:
+		if !support {
+			return StatusMaintenance
+		}
+	}
+	return StatusSupported
+}
+
+// SupportEndDate renders the support field, which the API returns either as
+// a date or as a boolean. Support given as true lasts until EOL.
+func SupportEndDate(v SoftwareVersion) string {
+	switch supportValue := v.Support.(type) {
+	case string:
+		return supportValue
+	case bool:
+		if !supportValue {
+			return "No Support"
+		}
+		if eol := EOLDate(v); eol != "" {
+			return eol
+		}
+		return "the EOL date"
+	default:
+		return "Unknown"
+	}
+}
+
+// Granularities are the values of MatchOptions.Granularity besides the
+// default, empty one.
+var Granularities = []string{"major", "minor", "exact"}
+
+// Picks are the values of MatchOptions.Pick.
+var Picks = []string{"newest", "oldest"}
+
+// MatchOptions tune how MatchCycle matches versions to cycles.
+type MatchOptions struct {
+	// Granularity is how much of a version has to make up its cycle's name:
+	// "major" (the first segment), "minor" (the first two) or "exact" (all
+	// of it). Empty means any prefix does.
+	Granularity string
+	// Pick is which cycle a bare version like "18" matches when a product
+	// only has cycles below it, like "18.0" and "18.1": "newest" (the
+	// default) or "oldest".
+	Pick string
+}
+
+// MatchCycle finds the release cycle a version belongs to. An exact cycle
+// match wins; otherwise the longest cycle the version starts with (on a dot
+// boundary) is used, so "18.0.0" matches the "18" cycle. Failing that, a
+// version with cycles below it matches the newest of them (or the oldest
+// with Pick), so "18" matches "18.1" of "18.0" and "18.1". A Granularity
+// replaces this with matching only the cycle named after the major or minor
+// version, or only an exact match.
+func MatchCycle(versions []SoftwareVersion, version string, opts MatchOptions) (SoftwareVersion, bool) {
+	switch opts.Granularity {
+	case "major", "minor", "exact":
+		cycle := version
+		segments := strings.Split(version, ".")
+		if opts.Granularity == "major" {
+			cycle = segments[0]
+		} else if opts.Granularity == "minor" {
+			if len(segments) < 2 {
+				return SoftwareVersion{}, false
+			}
+			cycle = strings.Join(segments[:2], ".")
+		}
+		for _, v := range versions {
+			if v.Cycle == cycle {
+				return v, true
+			}
+		}
+		return SoftwareVersion{}, false
+	}
+
+	var best SoftwareVersion
+	found := false
+	for _, v := range versions {
+		if v.Cycle == version {
+			return v, true
+		}
+		if strings.HasPrefix(version, v.Cycle+".") && (!found || len(v.Cycle) > len(best.Cycle)) {
+			best, found = v, true
+		}
+	}
+	if found {
+		return best, true
+	}
+
+	for _, v := range versions {
+		if !strings.HasPrefix(v.Cycle, version+".") {
+			continue
+		}
+		order := CompareVersions(v.Cycle, best.Cycle)
+		if !found || (opts.Pick == "oldest" && order < 0) || (opts.Pick != "oldest" && order > 0) {
+			best, found = v, true
+		}
+	}
+	return best, found
+}
+
+// CompareVersions compares two dotted versions segment by segment, numerically
+// where both segments are numbers. It returns -1, 0 or 1.
+func CompareVersions(a string, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package endoflife
+
+import "testing"
+
+func TestCycleStatus(t *testing.T) {
+	const now = "2025-04-30"
+	tests := []struct {
+		name string
+		v    SoftwareVersion
+		want Status
+	}{
+		{"support date ahead", SoftwareVersion{EOL: "2026-01-01", Support: "2025-06-01"}, StatusSupported},
+		{"support date passed", SoftwareVersion{EOL: "2026-01-01", Support: "2025-01-01"}, StatusMaintenance},
+		{"support true", SoftwareVersion{EOL: "2026-01-01", Support: true}, StatusSupported},
+		{"support false", SoftwareVersion{EOL: "2026-01-01", Support: false}, StatusMaintenance},
+		{"no support field", SoftwareVersion{EOL: "2026-01-01"}, StatusSupported},
+		{"support false and EOL passed", SoftwareVersion{EOL: "2025-01-01", Support: false}, StatusEOL},
+		{"EOL false", SoftwareVersion{EOL: false, Support: false}, StatusMaintenance},
+		{"no EOL", SoftwareVersion{Support: "2025-01-01"}, StatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CycleStatus(tt.v, now); got != tt.want {
+				t.Errorf("CycleStatus = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportEndDate(t *testing.T) {
+	tests := []struct {
+		name string
+		v    SoftwareVersion
+		want string
+	}{
+		{"support true, EOL ahead", SoftwareVersion{EOL: "2026-01-01", Support: true}, "2026-01-01"},
+		{"support true, EOL passed", SoftwareVersion{EOL: "2024-01-01", Support: true}, "2024-01-01"},
+		{"support true, EOL true", SoftwareVersion{EOL: true, Support: true}, "the EOL date"},
+		{"support true, no EOL", SoftwareVersion{Support: true}, "the EOL date"},
+		{"support false", SoftwareVersion{EOL: "2026-01-01", Support: false}, "No Support"},
+		{"support date", SoftwareVersion{EOL: "2026-01-01", Support: "2025-06-01"}, "2025-06-01"},
+		{"no support field", SoftwareVersion{EOL: "2026-01-01"}, "Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SupportEndDate(tt.v); got != tt.want {
+				t.Errorf("SupportEndDate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}